@@ -1,11 +1,10 @@
 package main
 
 import (
-	"fmt"
+	"encoding/json"
 	"github.com/Financial-Times/go-ft-http/fthttp"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	fthealth "github.com/Financial-Times/go-fthealth/v1_1"
@@ -44,6 +43,138 @@ func main() {
 		Desc:   "Duration Get requests should be cached for. e.g. 2h45m would set the max-age value to '7440' seconds",
 		EnvVar: "CACHE_DURATION",
 	})
+	staleWhileRevalidate := app.String(cli.StringOpt{
+		Name:   "stale-while-revalidate",
+		Value:  "30s",
+		Desc:   "Duration after their expiry cached things are still served while being refreshed in the background. 0 disables it",
+		EnvVar: "STALE_WHILE_REVALIDATE",
+	})
+	staleIfError := app.String(cli.StringOpt{
+		Name:   "stale-if-error",
+		Value:  "10m",
+		Desc:   "Duration after their expiry cached things are still served when public-concepts-api fails. 0 disables it",
+		EnvVar: "STALE_IF_ERROR",
+	})
+	cachePolicy := app.String(cli.StringOpt{
+		Name:   "cache-policy",
+		Value:  "",
+		Desc:   `JSON Cache-Control policy per response status and direct type, overriding the one derived from cache-duration, e.g. {"default":{"404":{"maxAge":60}},"types":{"Brand":{"200":{"maxAge":3600,"sMaxAge":7200}}}}`,
+		EnvVar: "CACHE_POLICY",
+	})
+	cacheSize := app.Int(cli.IntOpt{
+		Name:   "cache-size",
+		Value:  1000,
+		Desc:   "Maximum number of concepts kept in the in-memory cache in front of public-concepts-api. 0 disables the cache",
+		EnvVar: "CACHE_SIZE",
+	})
+	cacheTTL := app.String(cli.StringOpt{
+		Name:   "cache-ttl",
+		Value:  "30s",
+		Desc:   "Duration concepts are kept in the in-memory cache before being fetched again from public-concepts-api",
+		EnvVar: "CACHE_TTL",
+	})
+	negativeCacheSize := app.Int(cli.IntOpt{
+		Name:   "negative-cache-size",
+		Value:  1000,
+		Desc:   "Maximum number of uuids public-concepts-api found no concept for kept in memory. 0 disables the negative cache",
+		EnvVar: "NEGATIVE_CACHE_SIZE",
+	})
+	negativeCacheTTL := app.String(cli.StringOpt{
+		Name:   "negative-cache-ttl",
+		Value:  "10s",
+		Desc:   "Duration uuids public-concepts-api found no concept for are remembered, also used as max-age of 404 responses. 0 disables it",
+		EnvVar: "NEGATIVE_CACHE_TTL",
+	})
+	cachePurgeToken := app.String(cli.StringOpt{
+		Name:   "cache-purge-token",
+		Value:  "",
+		Desc:   "Bearer token authenticating the requests to the cache purge admin endpoints. Empty disables the endpoints",
+		EnvVar: "CACHE_PURGE_TOKEN",
+	})
+	warmUpFile := app.String(cli.StringOpt{
+		Name:   "warm-up-file",
+		Value:  "",
+		Desc:   "File listing the uuids of the things preloaded in the cache at startup, one per line or as JSON lines with a uuid or id field",
+		EnvVar: "WARM_UP_FILE",
+	})
+	warmUpConcurrency := app.Int(cli.IntOpt{
+		Name:   "warm-up-concurrency",
+		Value:  5,
+		Desc:   "Maximum number of things fetched concurrently while warming up the cache",
+		EnvVar: "WARM_UP_CONCURRENCY",
+	})
+	circuitBreakerThreshold := app.Int(cli.IntOpt{
+		Name:   "circuit-breaker-threshold",
+		Value:  5,
+		Desc:   "Number of consecutive failed requests to public-concepts-api after which requests fail fast. 0 disables the circuit breaker",
+		EnvVar: "CIRCUIT_BREAKER_THRESHOLD",
+	})
+	circuitBreakerOpenTimeout := app.String(cli.StringOpt{
+		Name:   "circuit-breaker-open-timeout",
+		Value:  "10s",
+		Desc:   "Duration requests fail fast for once the circuit breaker is open, before public-concepts-api is probed again",
+		EnvVar: "CIRCUIT_BREAKER_OPEN_TIMEOUT",
+	})
+	retryMaxAttempts := app.Int(cli.IntOpt{
+		Name:   "retry-max-attempts",
+		Value:  3,
+		Desc:   "Maximum number of attempts of a GET request for a concept to public-concepts-api, including the first one",
+		EnvVar: "RETRY_MAX_ATTEMPTS",
+	})
+	retryBaseDelay := app.String(cli.StringOpt{
+		Name:   "retry-base-delay",
+		Value:  "50ms",
+		Desc:   "Base delay of the exponential backoff between attempts of a request to public-concepts-api",
+		EnvVar: "RETRY_BASE_DELAY",
+	})
+	retryMaxDelay := app.String(cli.StringOpt{
+		Name:   "retry-max-delay",
+		Value:  "1s",
+		Desc:   "Maximum delay between attempts of a request to public-concepts-api",
+		EnvVar: "RETRY_MAX_DELAY",
+	})
+	retryStatusCodes := app.Ints(cli.IntsOpt{
+		Name:   "retry-status-codes",
+		Value:  []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		Desc:   "HTTP status codes returned by public-concepts-api for which the request is retried",
+		EnvVar: "RETRY_STATUS_CODES",
+	})
+	maxBatchSize := app.Int(cli.IntOpt{
+		Name:   "max-batch-size",
+		Value:  500,
+		Desc:   "Maximum number of uuids which can be requested in a single batch operation. 0 leaves batches unlimited",
+		EnvVar: "MAX_BATCH_SIZE",
+	})
+	batchConcurrency := app.Int(cli.IntOpt{
+		Name:   "batch-concurrency",
+		Value:  10,
+		Desc:   "Maximum number of things fetched concurrently for a single batch operation. 0 fetches all of them concurrently",
+		EnvVar: "BATCH_CONCURRENCY",
+	})
+	maxRedirects := app.Int(cli.IntOpt{
+		Name:   "max-redirects",
+		Value:  3,
+		Desc:   "Maximum number of redirects between alternate uuids followed when resolving a thing",
+		EnvVar: "MAX_REDIRECTS",
+	})
+	upstreamMaxInFlight := app.Int(cli.IntOpt{
+		Name:   "upstream-max-in-flight",
+		Value:  100,
+		Desc:   "Maximum number of requests in flight to public-concepts-api across all requests. 0 leaves them unlimited",
+		EnvVar: "UPSTREAM_MAX_IN_FLIGHT",
+	})
+	upstreamQueueTimeout := app.String(cli.StringOpt{
+		Name:   "upstream-queue-timeout",
+		Value:  "2s",
+		Desc:   "Duration a request to public-concepts-api waits for an in-flight slot before failing",
+		EnvVar: "UPSTREAM_QUEUE_TIMEOUT",
+	})
+	requestTimeout := app.String(cli.StringOpt{
+		Name:   "request-timeout",
+		Value:  "20s",
+		Desc:   "Duration after which a request gives up waiting for public-concepts-api. 0 only relies on the client going away",
+		EnvVar: "REQUEST_TIMEOUT",
+	})
 	logLevel := app.String(cli.StringOpt{
 		Name:   "logLevel",
 		Value:  "info",
@@ -56,6 +187,12 @@ func main() {
 		Desc:   "Public concepts API endpoint URL.",
 		EnvVar: "CONCEPTS_API",
 	})
+	conceptsDir := app.String(cli.StringOpt{
+		Name:   "concepts-dir",
+		Value:  "",
+		Desc:   "Directory of concept JSON files to serve things from instead of public-concepts-api, e.g. things/fixtures",
+		EnvVar: "CONCEPTS_DIR",
+	})
 
 	log.InitLogger(*appSystemCode, *logLevel)
 	log.Infof("[Startup] public-things-api is starting ")
@@ -63,29 +200,122 @@ func main() {
 	httpClient := fthttp.NewClient(30*time.Second, "PAC", *appSystemCode)
 	app.Action = func() {
 		log.Infof("public-things-api will listen on port: %s", *port)
-		runServer(*port, *cacheDuration, *env, *publicConceptsApiURL, httpClient)
+		staleWhileRevalidateDuration := parseDuration("stale-while-revalidate", *staleWhileRevalidate)
+		staleIfErrorDuration := parseDuration("stale-if-error", *staleIfError)
+		negativeCacheDuration := parseDuration("negative-cache-ttl", *negativeCacheTTL)
+		options := []things.Option{
+			things.WithConceptCache(*cacheSize, parseDuration("cache-ttl", *cacheTTL)),
+			things.WithNegativeCache(*negativeCacheSize, negativeCacheDuration),
+			things.WithPurgeToken(*cachePurgeToken),
+			things.WithWarmUp(readWarmUpFile(*warmUpFile), *warmUpConcurrency),
+			things.WithStaleServing(staleWhileRevalidateDuration, staleIfErrorDuration),
+			things.WithCachePolicy(parseCachePolicy(*cachePolicy, things.DefaultCachePolicy(
+				parseDuration("cache-duration", *cacheDuration), staleWhileRevalidateDuration, staleIfErrorDuration,
+				negativeCacheDuration))),
+			things.WithCircuitBreaker(*circuitBreakerThreshold, parseDuration("circuit-breaker-open-timeout", *circuitBreakerOpenTimeout)),
+			// wraps the circuit breaker so that saturation does not count as a public-concepts-api failure
+			things.WithUpstreamConcurrency(*upstreamMaxInFlight, parseDuration("upstream-queue-timeout", *upstreamQueueTimeout)),
+			things.WithRetries(things.RetryPolicy{
+				MaxAttempts:          *retryMaxAttempts,
+				BaseDelay:            parseDuration("retry-base-delay", *retryBaseDelay),
+				MaxDelay:             parseDuration("retry-max-delay", *retryMaxDelay),
+				RetryableStatusCodes: *retryStatusCodes,
+			}),
+			things.WithMaxBatchSize(*maxBatchSize),
+			things.WithBatchConcurrency(*batchConcurrency),
+			things.WithMaxRedirects(*maxRedirects),
+			things.WithRequestTimeout(parseDuration("request-timeout", *requestTimeout)),
+		}
+		var source things.ConceptSource = things.NewConceptsAPI(httpClient, *publicConceptsApiURL)
+		if *conceptsDir != "" {
+			source = loadConceptsDir(*conceptsDir)
+		}
+		runServer(*port, *env, source, options...)
 
 	}
 	log.InitLogger(*appSystemCode, *logLevel)
 	log.WithFields(map[string]interface{}{
-		"CACHE_DURATION": *cacheDuration,
-		"LOG_LEVEL":      *logLevel,
+		"CACHE_DURATION":               *cacheDuration,
+		"STALE_WHILE_REVALIDATE":       *staleWhileRevalidate,
+		"STALE_IF_ERROR":               *staleIfError,
+		"CACHE_POLICY":                 *cachePolicy,
+		"CACHE_SIZE":                   *cacheSize,
+		"CACHE_TTL":                    *cacheTTL,
+		"NEGATIVE_CACHE_SIZE":          *negativeCacheSize,
+		"NEGATIVE_CACHE_TTL":           *negativeCacheTTL,
+		"CACHE_PURGE_ENABLED":          *cachePurgeToken != "",
+		"WARM_UP_FILE":                 *warmUpFile,
+		"WARM_UP_CONCURRENCY":          *warmUpConcurrency,
+		"CIRCUIT_BREAKER_THRESHOLD":    *circuitBreakerThreshold,
+		"CIRCUIT_BREAKER_OPEN_TIMEOUT": *circuitBreakerOpenTimeout,
+		"RETRY_MAX_ATTEMPTS":           *retryMaxAttempts,
+		"RETRY_BASE_DELAY":             *retryBaseDelay,
+		"RETRY_MAX_DELAY":              *retryMaxDelay,
+		"RETRY_STATUS_CODES":           *retryStatusCodes,
+		"MAX_BATCH_SIZE":               *maxBatchSize,
+		"BATCH_CONCURRENCY":            *batchConcurrency,
+		"MAX_REDIRECTS":                *maxRedirects,
+		"UPSTREAM_MAX_IN_FLIGHT":       *upstreamMaxInFlight,
+		"UPSTREAM_QUEUE_TIMEOUT":       *upstreamQueueTimeout,
+		"REQUEST_TIMEOUT":              *requestTimeout,
+		"CONCEPTS_DIR":                 *conceptsDir,
+		"LOG_LEVEL":                    *logLevel,
 	}).Info("Starting app with arguments")
 	app.Run(os.Args)
 }
 
-func runServer(port string, cacheDuration string, env string, publicConceptsApiURL string,
-	httpClient *http.Client) {
+func parseDuration(name string, value string) time.Duration {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Failed to parse %s duration string, %v", name, err)
+	}
+	return duration
+}
+
+func loadConceptsDir(dir string) *things.DirectorySource {
+	source, err := things.NewDirectorySource(dir)
+	if err != nil {
+		log.Fatalf("Failed to load concepts directory, %v", err)
+	}
+	return source
+}
 
-	if duration, durationErr := time.ParseDuration(cacheDuration); durationErr != nil {
-		log.Fatalf("Failed to parse cache duration string, %v", durationErr)
-	} else {
-		things.CacheControlHeader = fmt.Sprintf("max-age=%s, public", strconv.FormatFloat(duration.Seconds(), 'f', 0, 64))
+func readWarmUpFile(path string) []string {
+	if path == "" {
+		return nil
 	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open warm-up file, %v", err)
+	}
+	defer file.Close()
+
+	uuids, err := things.ReadWarmUpUUIDs(file)
+	if err != nil {
+		log.Fatalf("Failed to read warm-up file, %v", err)
+	}
+	return uuids
+}
+
+// parseCachePolicy overrides the classes of responses of the default policy with the ones of the JSON config.
+func parseCachePolicy(config string, policy things.CachePolicy) things.CachePolicy {
+	if config == "" {
+		return policy
+	}
+	if err := json.Unmarshal([]byte(config), &policy); err != nil {
+		log.Fatalf("Failed to parse cache policy, %v", err)
+	}
+	if err := policy.Validate(); err != nil {
+		log.Fatalf("Invalid cache policy, %v", err)
+	}
+	return policy
+}
 
+func runServer(port string, env string, source things.ConceptSource, handlerOptions ...things.Option) {
 	servicesRouter := mux.NewRouter()
 
-	handler := things.NewHandler(httpClient, publicConceptsApiURL)
+	handler := things.NewSourceHandler(source, handlerOptions...)
+	go handler.WarmUp()
 
 	// Healthchecks and standards first
 	healthCheck := fthealth.TimedHealthCheck{
@@ -0,0 +1,136 @@
+package things
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/rcrowley/go-metrics"
+)
+
+// ErrCircuitOpen is returned instead of calling public-concepts-api while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker to public-concepts-api is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreakerClient wraps an HttpClient and stops calling it after a number of consecutive failures.
+// Transport errors and 5xx responses count as failures, unless the request was cancelled or timed out on our side.
+// Once openTimeout has elapsed a single probe request is let through: the circuit closes again if it succeeds and
+// reopens otherwise.
+type circuitBreakerClient struct {
+	sync.Mutex
+	client      HttpClient
+	threshold   int
+	openTimeout time.Duration
+	state       breakerState
+	failures    int
+	openedAt    time.Time
+	now         func() time.Time
+	trips       metrics.Counter
+}
+
+func newCircuitBreakerClient(client HttpClient, threshold int, openTimeout time.Duration) *circuitBreakerClient {
+	return &circuitBreakerClient{
+		client:      client,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         time.Now,
+		trips:       metrics.GetOrRegisterCounter("things.upstream.circuit_breaker.trips", metrics.DefaultRegistry),
+	}
+}
+
+func (cb *circuitBreakerClient) Do(req *http.Request) (*http.Response, error) {
+	if !cb.allow() {
+		return nil, ErrCircuitOpen
+	}
+	resp, err := cb.client.Do(req)
+	if req.Context().Err() != nil {
+		cb.abandon()
+		return resp, err
+	}
+	cb.record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+func (cb *circuitBreakerClient) currentState() breakerState {
+	cb.Lock()
+	defer cb.Unlock()
+	return cb.state
+}
+
+func (cb *circuitBreakerClient) allow() bool {
+	cb.Lock()
+	defer cb.Unlock()
+
+	switch cb.state {
+	case breakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openTimeout {
+			return false
+		}
+		logger.Info("Circuit breaker to public-concepts-api is half-open, probing")
+		cb.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// only the probe request is allowed until its outcome is known
+		return false
+	default:
+		return true
+	}
+}
+
+// abandon ignores the outcome of a request whose context is done, which says nothing about public-concepts-api.
+// An abandoned probe leaves the circuit open, ready to let the next request probe again.
+func (cb *circuitBreakerClient) abandon() {
+	cb.Lock()
+	defer cb.Unlock()
+
+	if cb.state == breakerHalfOpen {
+		cb.state = breakerOpen
+	}
+}
+
+func (cb *circuitBreakerClient) record(success bool) {
+	cb.Lock()
+	defer cb.Unlock()
+
+	// late outcomes of requests started before the circuit opened are ignored
+	if cb.state == breakerOpen {
+		return
+	}
+
+	if success {
+		if cb.state == breakerHalfOpen {
+			logger.Info("Circuit breaker to public-concepts-api is closed")
+		}
+		cb.state = breakerClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == breakerHalfOpen || cb.failures >= cb.threshold {
+		logger.Warnf("Circuit breaker to public-concepts-api is open after %d consecutive failures", cb.failures)
+		cb.trips.Inc(1)
+		cb.state = breakerOpen
+		cb.openedAt = cb.now()
+	}
+}
@@ -0,0 +1,161 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fthealth "github.com/Financial-Times/go-fthealth/v1_1"
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	mockClient := mockHTTPClient{statusCode: http.StatusInternalServerError}
+	breaker := newCircuitBreakerClient(&mockClient, 3, time.Minute)
+	req, _ := http.NewRequest("GET", "/concepts/"+canonicalUUID, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := breaker.Do(req)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, breakerOpen, breaker.currentState())
+
+	_, err := breaker.Do(req)
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, 3, mockClient.calls, "open circuit should not call the wrapped client")
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	mockClient := mockHTTPClient{}
+	breaker := newCircuitBreakerClient(&mockClient, 2, time.Minute)
+	req, _ := http.NewRequest("GET", "/concepts/"+canonicalUUID, nil)
+
+	mockClient.err = errors.New("connection refused")
+	breaker.Do(req)
+	mockClient.err = nil
+	mockClient.statusCode = http.StatusOK
+	breaker.Do(req)
+	mockClient.err = errors.New("connection refused")
+	breaker.Do(req)
+
+	assert.Equal(t, breakerClosed, breaker.currentState())
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	now := time.Now()
+	mockClient := mockHTTPClient{statusCode: http.StatusServiceUnavailable}
+	breaker := newCircuitBreakerClient(&mockClient, 1, time.Minute)
+	breaker.now = func() time.Time { return now }
+	req, _ := http.NewRequest("GET", "/concepts/"+canonicalUUID, nil)
+
+	breaker.Do(req)
+	assert.Equal(t, breakerOpen, breaker.currentState())
+
+	now = now.Add(2 * time.Minute)
+	breaker.Do(req)
+	assert.Equal(t, breakerOpen, breaker.currentState(), "failed probe should reopen the circuit")
+	assert.Equal(t, 2, mockClient.calls)
+
+	now = now.Add(2 * time.Minute)
+	mockClient.statusCode = http.StatusOK
+	breaker.Do(req)
+	assert.Equal(t, breakerClosed, breaker.currentState(), "successful probe should close the circuit")
+}
+
+func TestCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	now := time.Now()
+	mockClient := mockHTTPClient{err: context.Canceled}
+	breaker := newCircuitBreakerClient(&mockClient, 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequest("GET", "/concepts/"+canonicalUUID, nil)
+	req = req.WithContext(ctx)
+
+	breaker.Do(req)
+	breaker.Do(req)
+	assert.Equal(t, breakerClosed, breaker.currentState(), "cancelled requests should not count as failures")
+	assert.Equal(t, 0, breaker.failures)
+
+	breaker.state, breaker.openedAt = breakerOpen, now.Add(-2*time.Minute)
+	breaker.Do(req)
+	assert.Equal(t, breakerOpen, breaker.currentState(), "a cancelled probe should leave the circuit open")
+
+	mockClient.err, mockClient.statusCode = nil, http.StatusOK
+	probe, _ := http.NewRequest("GET", "/concepts/"+canonicalUUID, nil)
+	_, err := breaker.Do(probe)
+	assert.NoError(t, err, "the next request should probe again")
+	assert.Equal(t, breakerClosed, breaker.currentState())
+}
+
+func TestGetThingFailsFastWhenCircuitIsOpen(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	mockClient := mockHTTPClient{err: errors.New("connection refused")}
+	router := mux.NewRouter()
+	handler := NewHandler(&mockClient, "localhost:8080", WithCircuitBreaker(1, time.Minute))
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, `{"message":"Public concepts API is unavailable, not getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb until it recovers"}`, rr.Body.String())
+	assert.Equal(t, 1, mockClient.calls)
+}
+
+func TestHealthCheckReportsCircuitBreakerState(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	mockClient := mockHTTPClient{statusCode: http.StatusOK}
+	handler := NewHandler(&mockClient, "localhost:8080", WithCircuitBreaker(1, time.Minute))
+
+	output, err := handler.HealthCheck().Checker()
+	assert.NoError(t, err)
+	assert.Equal(t, "Public Concepts API is healthy, circuit breaker is closed", output)
+
+	for i := 0; i < 3; i++ {
+		handler.HealthCheck().Checker()
+	}
+	assert.Equal(t, breakerClosed, handler.conceptsAPI.breaker.currentState(), "health checks should not go through the circuit breaker")
+
+	mockClient.statusCode = http.StatusInternalServerError
+	_, err = handler.HealthCheck().Checker()
+	assert.EqualError(t, err, "health check returned a non-200 HTTP status: 500, circuit breaker is closed")
+	assert.Equal(t, breakerClosed, handler.conceptsAPI.breaker.currentState(), "failed health checks should not open the circuit")
+
+	thingsRouter := mux.NewRouter()
+	handler.RegisterHandlers(thingsRouter)
+	serveThing(thingsRouter, "/things/"+canonicalUUID, nil)
+	assert.Equal(t, breakerOpen, handler.conceptsAPI.breaker.currentState())
+
+	mockClient.statusCode = http.StatusOK
+	output, err = handler.HealthCheck().Checker()
+	assert.NoError(t, err)
+	assert.Equal(t, "Public Concepts API is healthy, circuit breaker is open", output)
+
+	healthCheck := fthealth.TimedHealthCheck{
+		HealthCheck: fthealth.HealthCheck{
+			SystemCode: "public-things-api",
+			Checks:     []fthealth.Check{handler.HealthCheck()},
+		},
+		Timeout: 10 * time.Second,
+	}
+	healthRouter := mux.NewRouter()
+	healthRouter.HandleFunc("/__health", fthealth.Handler(healthCheck))
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/__health", nil)
+	healthRouter.ServeHTTP(rr, req)
+	assert.Contains(t, rr.Body.String(), "circuit breaker is open")
+}
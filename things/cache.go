@@ -0,0 +1,203 @@
+package things
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// conceptCache is a bounded, TTL aware LRU cache of mapped concepts keyed by
+// the requested uuid and the set of requested relationships. Expired entries
+// are retained for a while so that they can still be served stale.
+type conceptCache struct {
+	sync.Mutex
+	size    int
+	ttl     time.Duration
+	retain  time.Duration
+	entries map[string]*list.Element
+	lru     *list.List
+	now     func() time.Time
+	// purges counts the purges, so that concepts read before one are not cached after it
+	purges uint64
+
+	hits      metrics.Counter
+	misses    metrics.Counter
+	evictions metrics.Counter
+	staleHits metrics.Counter
+}
+
+type cacheEntry struct {
+	key     string
+	concept Concept
+	expires time.Time
+}
+
+func newConceptCache(size int, ttl time.Duration) *conceptCache {
+	return newMeteredCache("things.cache", size, ttl)
+}
+
+// newNotFoundCache builds a cache remembering the keys public-concepts-api found no concept for.
+func newNotFoundCache(size int, ttl time.Duration) *conceptCache {
+	return newMeteredCache("things.cache.not_found", size, ttl)
+}
+
+func newMeteredCache(name string, size int, ttl time.Duration) *conceptCache {
+	return &conceptCache{
+		size:      size,
+		ttl:       ttl,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+		now:       time.Now,
+		hits:      metrics.GetOrRegisterCounter(name+".hits", metrics.DefaultRegistry),
+		misses:    metrics.GetOrRegisterCounter(name+".misses", metrics.DefaultRegistry),
+		evictions: metrics.GetOrRegisterCounter(name+".evictions", metrics.DefaultRegistry),
+		staleHits: metrics.GetOrRegisterCounter(name+".stale_hits", metrics.DefaultRegistry),
+	}
+}
+
+// cacheKey builds the cache key for a uuid, ignoring the order in which
+// relationships were requested.
+func cacheKey(uuid string, relationships []string) string {
+	sorted := make([]string, len(relationships))
+	copy(sorted, relationships)
+	sort.Strings(sorted)
+	return uuid + "?" + strings.Join(sorted, ",")
+}
+
+func (c *conceptCache) get(key string) (Concept, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		c.misses.Inc(1)
+		return Concept{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if c.now().After(entry.expires) {
+		if c.now().After(entry.expires.Add(c.retain)) {
+			c.removeElement(element)
+		}
+		c.misses.Inc(1)
+		return Concept{}, false
+	}
+	c.lru.MoveToFront(element)
+	c.hits.Inc(1)
+	return entry.concept, true
+}
+
+// stale returns an expired entry still retained by the cache, provided it expired at most maxStaleness ago.
+func (c *conceptCache) stale(key string, maxStaleness time.Duration) (Concept, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return Concept{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	staleness := c.now().Sub(entry.expires)
+	if staleness <= 0 || staleness > maxStaleness {
+		return Concept{}, false
+	}
+	c.lru.MoveToFront(element)
+	c.staleHits.Inc(1)
+	return entry.concept, true
+}
+
+// retained returns the entry the cache retains for the key, expired or not, leaving the metrics and the recency of
+// the entry untouched.
+func (c *conceptCache) retained(key string) (Concept, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		return Concept{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if c.now().After(entry.expires.Add(c.retain)) {
+		return Concept{}, false
+	}
+	return entry.concept, true
+}
+
+func (c *conceptCache) remove(key string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, found := c.entries[key]; found {
+		c.removeElement(element)
+	}
+}
+
+// generation returns the number of purges so far, to be given to setUnlessPurged by the lookups starting.
+func (c *conceptCache) generation() uint64 {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.purges
+}
+
+func (c *conceptCache) set(key string, concept Concept) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.setLocked(key, concept)
+}
+
+// setUnlessPurged caches the concept unless the cache was purged since the generation the lookup of the concept
+// started at, as the concept may then be older than the purge.
+func (c *conceptCache) setUnlessPurged(key string, concept Concept, generation uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.purges == generation {
+		c.setLocked(key, concept)
+	}
+}
+
+func (c *conceptCache) setLocked(key string, concept Concept) {
+	expires := c.now().Add(c.ttl)
+	if element, found := c.entries[key]; found {
+		entry := element.Value.(*cacheEntry)
+		entry.concept = concept
+		entry.expires = expires
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key, concept, expires})
+	for c.lru.Len() > c.size {
+		c.removeElement(c.lru.Back())
+		c.evictions.Inc(1)
+	}
+}
+
+// purge removes the entries matching the given predicate, expired or not, and returns how many were removed.
+func (c *conceptCache) purge(matches func(key string, concept Concept) bool) int {
+	c.Lock()
+	defer c.Unlock()
+
+	c.purges++
+	purged := 0
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*cacheEntry)
+		if matches(entry.key, entry.concept) {
+			c.removeElement(element)
+			purged++
+		}
+		element = next
+	}
+	return purged
+}
+
+func (c *conceptCache) removeElement(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,236 @@
+package things
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheKeyIgnoresRelationshipOrder(t *testing.T) {
+	assert.Equal(t, cacheKey(canonicalUUID, []string{"related", "broader"}), cacheKey(canonicalUUID, []string{"broader", "related"}))
+	assert.NotEqual(t, cacheKey(canonicalUUID, []string{"broader"}), cacheKey(canonicalUUID, nil))
+}
+
+func TestConceptCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newConceptCache(2, time.Minute)
+
+	cache.set("a", Concept{ID: "a"})
+	cache.set("b", Concept{ID: "b"})
+	_, found := cache.get("a")
+	assert.True(t, found)
+
+	cache.set("c", Concept{ID: "c"})
+
+	_, found = cache.get("b")
+	assert.False(t, found, "least recently used entry should have been evicted")
+	thing, found := cache.get("a")
+	assert.True(t, found)
+	assert.Equal(t, "a", thing.ID)
+	_, found = cache.get("c")
+	assert.True(t, found)
+}
+
+func TestConceptCacheExpiresEntries(t *testing.T) {
+	now := time.Now()
+	cache := newConceptCache(10, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", Concept{ID: "a"})
+	_, found := cache.get("a")
+	assert.True(t, found)
+
+	now = now.Add(2 * time.Minute)
+	_, found = cache.get("a")
+	assert.False(t, found, "expired entry should not be served")
+}
+
+func TestGetThingServedFromCache(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	mockClient := mockHTTPClient{
+		resp:       getCompleteThingAsConcept,
+		statusCode: 200,
+	}
+	router := mux.NewRouter()
+	handler := NewHandler(&mockClient, "localhost:8080", WithConceptCache(10, time.Minute))
+	handler.RegisterHandlers(router)
+
+	for i := 0; i < 3; i++ {
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+	}
+	assert.Equal(t, 1, mockClient.calls, "only the first request should reach public-concepts-api")
+}
+
+func TestConceptCacheRetainsExpiredEntriesForStaleServing(t *testing.T) {
+	now := time.Now()
+	cache := newConceptCache(10, time.Minute)
+	cache.retain = time.Hour
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", Concept{ID: "a"})
+	_, found := cache.stale("a", time.Hour)
+	assert.False(t, found, "fresh entry should not be reported as stale")
+
+	now = now.Add(2 * time.Minute)
+	_, found = cache.get("a")
+	assert.False(t, found, "expired entry should not be served fresh")
+	_, found = cache.stale("a", 30*time.Second)
+	assert.False(t, found, "entry expired for longer than the allowed staleness should not be served")
+	thing, found := cache.stale("a", time.Hour)
+	assert.True(t, found)
+	assert.Equal(t, "a", thing.ID)
+
+	now = now.Add(2 * time.Hour)
+	cache.get("a")
+	assert.Equal(t, 0, cache.lru.Len(), "entry should be dropped once it's no longer retained")
+}
+
+// staleTestHandler returns a handler caching things for a minute, along with a function moving its clock forward.
+func staleTestHandler(client HttpClient, options ...Option) (*mux.Router, func(time.Duration)) {
+	handler := NewHandler(client, "http://localhost:8080", append([]Option{WithConceptCache(10, time.Minute)}, options...)...)
+	now := time.Now()
+	handler.cache.now = func() time.Time { return now }
+	router := mux.NewRouter()
+	handler.RegisterHandlers(router)
+
+	return router, func(d time.Duration) {
+		handler.cache.Lock()
+		now = now.Add(d)
+		handler.cache.Unlock()
+	}
+}
+
+func TestGetThingStaleWhileRevalidate(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	path := "/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+	client := &pathHTTPClient{responses: map[string]mockResponse{path: {statusCode: 200, body: getCompleteThingAsConcept}}}
+	router, advance := staleTestHandler(client, WithStaleServing(time.Minute, 0))
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	rr := serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Warning"))
+
+	advance(90 * time.Second)
+	rr = serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+	assert.Equal(t, warningStale, rr.Header().Get("Warning"))
+	assert.Equal(t, "true", rr.Header().Get("X-Stale"))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		client.Lock()
+		calls := client.calls[path]
+		client.Unlock()
+		if calls == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale thing should have been refreshed in the background")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the refresh may still be storing the concept
+	for i := 0; i < 100; i++ {
+		if rr = serveThing(router, url, nil); rr.Header().Get("Warning") == "" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Empty(t, rr.Header().Get("Warning"), "refreshed thing should be served fresh")
+}
+
+func TestGetThingStaleIfError(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	path := "/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+	client := &pathHTTPClient{responses: map[string]mockResponse{path: {statusCode: 200, body: getCompleteThingAsConcept}}}
+	router, advance := staleTestHandler(client, WithStaleServing(0, 10*time.Minute))
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	serveThing(router, url, nil)
+	client.Lock()
+	client.responses[path] = mockResponse{statusCode: http.StatusServiceUnavailable}
+	client.Unlock()
+
+	advance(5 * time.Minute)
+	rr := serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+	assert.Equal(t, warningRevalidationFailed, rr.Header().Get("Warning"))
+	assert.Equal(t, "true", rr.Header().Get("X-Stale"))
+
+	advance(10 * time.Minute)
+	rr = serveThing(router, url, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code, "thing stale for too long should not be served")
+}
+
+func TestGetThingsMarksStaleThings(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	path := "/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+	client := &pathHTTPClient{responses: map[string]mockResponse{path: {statusCode: 200, body: getCompleteThingAsConcept}}}
+	router, advance := staleTestHandler(client, WithStaleServing(0, 10*time.Minute))
+	url := "/things?uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	serveThing(router, url, nil)
+	client.Lock()
+	client.responses[path] = mockResponse{err: errors.New("connection refused")}
+	client.Unlock()
+
+	advance(5 * time.Minute)
+	rr := serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, warningRevalidationFailed, rr.Header().Get("Warning"))
+}
+
+func TestNotFoundThingsAreCached(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	path := "/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+	client := &pathHTTPClient{}
+	handler := NewHandler(client, "http://localhost:8080", WithNegativeCache(10, 10*time.Second), WithCachePolicy(testCachePolicy))
+	now := time.Now()
+	handler.notFound.now = func() time.Time { return now }
+	router := mux.NewRouter()
+	handler.RegisterHandlers(router)
+
+	for i := 0; i < 3; i++ {
+		rr := serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "max-age=10, public", rr.Header().Get("Cache-Control"))
+
+		rr = serveThing(router, "/things?partial=true&uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, `{"things":{},"notFound":["6773e864-78ab-4051-abc2-f4e9ab423ebb"]}`+"\n", rr.Body.String())
+	}
+	assert.Equal(t, 1, client.calls[path], "only the first lookup should reach public-concepts-api")
+
+	client.responses = map[string]mockResponse{path: {statusCode: 200, body: getCompleteThingAsConcept}}
+	now = now.Add(11 * time.Second)
+	rr := serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	assert.Equal(t, http.StatusOK, rr.Code, "thing created since should be found once the negative entry expired")
+	assert.Equal(t, 2, client.calls[path])
+}
+
+func TestNotFoundCacheDoesNotRememberErrors(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	path := "/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+	client := &pathHTTPClient{responses: map[string]mockResponse{path: {statusCode: http.StatusServiceUnavailable}}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080", WithNegativeCache(10, time.Minute))
+	handler.RegisterHandlers(router)
+
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+
+	assert.Equal(t, 2, client.calls[path])
+}
@@ -0,0 +1,134 @@
+package things
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CacheDirectives are the Cache-Control directives of a class of responses, in seconds.
+type CacheDirectives struct {
+	MaxAge               int `json:"maxAge"`
+	SMaxAge              int `json:"sMaxAge,omitempty"`
+	StaleWhileRevalidate int `json:"staleWhileRevalidate,omitempty"`
+	StaleIfError         int `json:"staleIfError,omitempty"`
+}
+
+func (d CacheDirectives) header() string {
+	header := fmt.Sprintf("max-age=%d, public", d.MaxAge)
+	if d.SMaxAge > 0 {
+		header += fmt.Sprintf(", s-maxage=%d", d.SMaxAge)
+	}
+	if d.StaleWhileRevalidate > 0 {
+		header += fmt.Sprintf(", stale-while-revalidate=%d", d.StaleWhileRevalidate)
+	}
+	if d.StaleIfError > 0 {
+		header += fmt.Sprintf(", stale-if-error=%d", d.StaleIfError)
+	}
+	return header
+}
+
+// CachePolicy decides the Cache-Control header of responses according to their status (200, 301 or 404) and the
+// direct type of the thing they describe. Types are keyed by the short name of their direct type, e.g. Brand, and
+// only override the classes of responses they list. Responses without directives get no Cache-Control header.
+type CachePolicy struct {
+	Default map[int]CacheDirectives            `json:"default"`
+	Types   map[string]map[int]CacheDirectives `json:"types,omitempty"`
+}
+
+var cacheableStatuses = map[int]bool{http.StatusOK: true, http.StatusMovedPermanently: true, http.StatusNotFound: true}
+
+// DefaultCachePolicy caches things and redirects to them for maxAge, letting caches serve them stale for the given
+// durations, and not found things for notFoundMaxAge, if positive.
+func DefaultCachePolicy(maxAge time.Duration, staleWhileRevalidate time.Duration, staleIfError time.Duration,
+	notFoundMaxAge time.Duration) CachePolicy {
+
+	directives := CacheDirectives{
+		MaxAge:               seconds(maxAge),
+		StaleWhileRevalidate: seconds(staleWhileRevalidate),
+		StaleIfError:         seconds(staleIfError),
+	}
+	policy := CachePolicy{Default: map[int]CacheDirectives{
+		http.StatusOK:               directives,
+		http.StatusMovedPermanently: directives,
+	}}
+	if notFoundMaxAge > 0 {
+		policy.Default[http.StatusNotFound] = CacheDirectives{MaxAge: seconds(notFoundMaxAge)}
+	}
+	return policy
+}
+
+func seconds(d time.Duration) int {
+	return int(d / time.Second)
+}
+
+// Validate checks the policy only covers cacheable responses with non negative directives.
+func (p CachePolicy) Validate() error {
+	if err := validateCacheDirectives("default", p.Default); err != nil {
+		return err
+	}
+	for directType, classes := range p.Types {
+		if err := validateCacheDirectives(directType, classes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCacheDirectives(name string, classes map[int]CacheDirectives) error {
+	for status, d := range classes {
+		if !cacheableStatuses[status] {
+			return fmt.Errorf("cache policy of %s covers HTTP status %d, only 200, 301 and 404 are supported", name, status)
+		}
+		if d.MaxAge < 0 || d.SMaxAge < 0 || d.StaleWhileRevalidate < 0 || d.StaleIfError < 0 {
+			return fmt.Errorf("cache policy of %s for HTTP status %d has negative directives", name, status)
+		}
+	}
+	return nil
+}
+
+func (p CachePolicy) directives(directType string, status int) (CacheDirectives, bool) {
+	if d, found := p.Types[extractFinalSectionOfString(directType)][status]; found && directType != "" {
+		return d, true
+	}
+	d, found := p.Default[status]
+	return d, found
+}
+
+// header returns the Cache-Control header of a response with the given status about a thing of the given direct
+// type, which is empty when unknown.
+func (p CachePolicy) header(directType string, status int) string {
+	if d, found := p.directives(directType, status); found {
+		return d.header()
+	}
+	return ""
+}
+
+// batchHeader returns the Cache-Control header of a batch response, which is as short lived as its most short lived
+// thing. Partial results with errors are not stored at all, as the errors are likely transient.
+func (p CachePolicy) batchHeader(result *ThingsResponse) string {
+	if len(result.Errors) > 0 {
+		return "no-store"
+	}
+	var shortest *CacheDirectives
+	for _, thing := range result.Things {
+		d, found := p.directives(thing.DirectType, http.StatusOK)
+		if !found {
+			return ""
+		}
+		if shortest == nil || d.MaxAge < shortest.MaxAge {
+			shortest = &d
+		}
+	}
+	if shortest == nil {
+		return p.header("", http.StatusOK)
+	}
+	return shortest.header()
+}
+
+// setCacheControl sets the Cache-Control header when the policy has one for the response.
+func setCacheControl(w http.ResponseWriter, header string) {
+	if header != "" {
+		w.Header().Set("Cache-Control", header)
+	}
+}
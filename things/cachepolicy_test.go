@@ -0,0 +1,128 @@
+package things
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCachePolicy = CachePolicy{
+	Default: map[int]CacheDirectives{
+		http.StatusOK:               {MaxAge: 30, StaleIfError: 600},
+		http.StatusMovedPermanently: {MaxAge: 300},
+		http.StatusNotFound:         {MaxAge: 10},
+	},
+	Types: map[string]map[int]CacheDirectives{
+		"Brand": {http.StatusOK: {MaxAge: 3600, SMaxAge: 7200}},
+		"Topic": {http.StatusOK: {MaxAge: 60}},
+	},
+}
+
+func TestDefaultCachePolicy(t *testing.T) {
+	policy := DefaultCachePolicy(2*time.Hour+45*time.Minute, 30*time.Second, 0, 0)
+
+	assert.Equal(t, "max-age=9900, public, stale-while-revalidate=30", policy.header("http://www.ft.com/ontology/product/Brand", http.StatusOK))
+	assert.Equal(t, "max-age=9900, public, stale-while-revalidate=30", policy.header("", http.StatusMovedPermanently))
+	assert.Empty(t, policy.header("", http.StatusNotFound))
+
+	policy = DefaultCachePolicy(time.Minute, 0, 0, 10*time.Second)
+	assert.Equal(t, "max-age=10, public", policy.header("", http.StatusNotFound))
+}
+
+func TestCachePolicyHeaderByType(t *testing.T) {
+	assert.Equal(t, "max-age=3600, public, s-maxage=7200", testCachePolicy.header("http://www.ft.com/ontology/product/Brand", http.StatusOK))
+	assert.Equal(t, "max-age=30, public, stale-if-error=600", testCachePolicy.header("http://www.ft.com/ontology/Person", http.StatusOK))
+	assert.Equal(t, "max-age=300, public", testCachePolicy.header("http://www.ft.com/ontology/product/Brand", http.StatusMovedPermanently),
+		"types should only override the classes of responses they list")
+	assert.Equal(t, "max-age=10, public", testCachePolicy.header("", http.StatusNotFound))
+}
+
+func TestCachePolicyBatchHeaderIsTheMostShortLived(t *testing.T) {
+	things := map[string]Concept{
+		"a": {DirectType: "http://www.ft.com/ontology/product/Brand"},
+		"b": {DirectType: "http://www.ft.com/ontology/Topic"},
+	}
+	assert.Equal(t, "max-age=60, public", testCachePolicy.batchHeader(&ThingsResponse{Things: things}))
+	assert.Equal(t, "max-age=30, public, stale-if-error=600", testCachePolicy.batchHeader(&ThingsResponse{Things: map[string]Concept{}}))
+
+	uncached := CachePolicy{Types: testCachePolicy.Types}
+	assert.Empty(t, uncached.batchHeader(&ThingsResponse{Things: map[string]Concept{"c": {DirectType: "http://www.ft.com/ontology/Person"}, "a": things["a"]}}))
+}
+
+func TestCachePolicyBatchHeaderWithErrors(t *testing.T) {
+	result := &ThingsResponse{
+		Things: map[string]Concept{"a": {DirectType: "http://www.ft.com/ontology/Topic"}},
+		Errors: map[string]string{"b": "public-concepts-api returned HTTP status 503"},
+	}
+	assert.Equal(t, "no-store", testCachePolicy.batchHeader(result))
+	assert.Equal(t, "no-store", CachePolicy{}.batchHeader(result), "errors should not be cached whatever the policy")
+}
+
+func TestCachePolicyValidate(t *testing.T) {
+	assert.NoError(t, testCachePolicy.Validate())
+
+	invalidStatus := CachePolicy{Types: map[string]map[int]CacheDirectives{"Brand": {http.StatusInternalServerError: {MaxAge: 10}}}}
+	assert.EqualError(t, invalidStatus.Validate(), "cache policy of Brand covers HTTP status 500, only 200, 301 and 404 are supported")
+
+	negative := CachePolicy{Default: map[int]CacheDirectives{http.StatusOK: {MaxAge: -1}}}
+	assert.Error(t, negative.Validate())
+}
+
+func TestGetThingCacheControl(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb": {statusCode: 200, body: getCompleteThingAsConcept},
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebc": {statusCode: 200, body: getCompleteThingAsConcept},
+	}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080", WithCachePolicy(testCachePolicy))
+	handler.RegisterHandlers(router)
+
+	rr := serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "max-age=3600, public, s-maxage=7200", rr.Header().Get("Cache-Control"))
+
+	rr = serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebc", nil)
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "max-age=300, public", rr.Header().Get("Cache-Control"))
+
+	rr = serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebd", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "max-age=10, public", rr.Header().Get("Cache-Control"))
+
+	rr = serveThing(router, "/things?uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "max-age=3600, public, s-maxage=7200", rr.Header().Get("Cache-Control"))
+}
+
+func TestGetThingErrorsAreNotCached(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb": {statusCode: http.StatusServiceUnavailable},
+	}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080", WithCachePolicy(testCachePolicy))
+	handler.RegisterHandlers(router)
+
+	rr := serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Empty(t, rr.Header().Get("Cache-Control"))
+}
+
+func TestGetThingsWithErrorsIsNotCached(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source := rdfTestSource()
+	source.On("Read", mock.Anything, thirdCanonicalUUID, []string(nil)).Return(Concept{}, false, errors.New("boom"))
+	router := sourceHandler(source, WithCachePolicy(testCachePolicy))
+
+	rr := serveThing(router, "/things?partial=true&uuid="+canonicalUUID+"&uuid="+thirdCanonicalUUID, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"errors"`)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,170 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/neo-model-utils-go/mapper"
+	"github.com/Financial-Times/transactionid-utils-go"
+)
+
+// ConceptsAPI is the ConceptSource reading concepts from public-concepts-api over HTTP.
+type ConceptsAPI struct {
+	client HttpClient
+	// checkClient is the client before being wrapped by the circuit breaker and the limiter, so that health checks
+	// neither count towards nor depend on the state of the circuit
+	checkClient HttpClient
+	conceptsURL string
+	breaker     *circuitBreakerClient
+	retries     RetryPolicy
+}
+
+// NewConceptsAPI returns the source reading concepts from public-concepts-api at conceptsURL with the given client.
+func NewConceptsAPI(client HttpClient, conceptsURL string) *ConceptsAPI {
+	return &ConceptsAPI{client: client, checkClient: client, conceptsURL: conceptsURL}
+}
+
+// Read gets the concept from public-concepts-api, conditionally when the context carries an If-Modified-Since date.
+func (api *ConceptsAPI) Read(ctx context.Context, UUID string, relationships []string) (Concept, bool, error) {
+	mappedConcept := Concept{}
+	transID, _ := transactionidutils.GetTransactionIDFromContext(ctx)
+
+	u, err := url.Parse(api.conceptsURL)
+	if err != nil {
+		msg := fmt.Sprint("URL of Concepts API is invalid")
+		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
+		return mappedConcept, false, err
+	}
+	u.Path = "/concepts/" + UUID
+	q := u.Query()
+	for _, relationship := range relationships {
+		q.Add("showRelationship", relationship)
+	}
+	u.RawQuery = q.Encode()
+	reqURL := u.String()
+	request, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		msg := fmt.Sprintf("failed to create request to %s", reqURL)
+		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
+		return mappedConcept, false, err
+	}
+	request = request.WithContext(ctx)
+
+	request.Header.Set("X-Request-Id", transID)
+	if since := IfModifiedSince(ctx); !since.IsZero() {
+		request.Header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+
+	resp, err := api.doWithRetries(request, UUID, transID)
+	if err != nil {
+		msg := fmt.Sprintf("request to %s was unsuccessful", reqURL)
+		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
+		return mappedConcept, false, classifyTransportError(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return mappedConcept, false, nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return mappedConcept, false, ErrNotModified
+	}
+
+	if err = classifyResponse(resp); err != nil {
+		msg := fmt.Sprintf("request to %s returned an unusable response", reqURL)
+		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
+		return mappedConcept, false, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		msg := fmt.Sprintf("failed to read response body: %v", resp.Body)
+		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
+		return mappedConcept, false, err
+	}
+	conceptsApiResponse, err := decodeConceptApiResponse(body)
+	if err != nil {
+		msg := fmt.Sprintf("failed to unmarshal response body: %v", body)
+		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
+		return mappedConcept, false, err
+	}
+	mappedConcept = mapConcept(UUID, conceptsApiResponse)
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		mappedConcept.lastModified = lastModified
+	}
+	return mappedConcept, true, nil
+}
+
+// mapConcept maps the concept returned by public-concepts-api for the requested uuid to a thing.
+func mapConcept(UUID string, conceptsApiResponse ConceptApiResponse) Concept {
+	mappedConcept := Concept{}
+	var altLabels []string
+	mappedConcept.ID = convertID(conceptsApiResponse.ID)
+	mappedConcept.APIURL = mapper.APIURL(UUID, []string{extractFinalSectionOfString(conceptsApiResponse.Type)}, "")
+	mappedConcept.PrefLabel = conceptsApiResponse.PrefLabel
+	mappedConcept.IsDeprecated = conceptsApiResponse.IsDeprecated
+	mappedConcept.DirectType = conceptsApiResponse.Type
+	mappedConcept.Types = mapper.FullTypeHierarchy(conceptsApiResponse.Type)
+
+	for _, keypair := range conceptsApiResponse.AlternativeLabels {
+		switch {
+		case keypair.Type == aliasLabelURI:
+			altLabels = append(altLabels, keypair.Value)
+		case keypair.Type == shortLabelURI:
+			mappedConcept.ShortLabel = keypair.Value
+		}
+	}
+	mappedConcept.Aliases = altLabels
+	mappedConcept.DescriptionXML = conceptsApiResponse.DescriptionXML
+	mappedConcept.ImageURL = conceptsApiResponse.ImageURL
+	for _, social := range conceptsApiResponse.Account {
+		mapTypedValues(&mappedConcept, social)
+	}
+	mappedConcept.ScopeNote = conceptsApiResponse.ScopeNote
+
+	if len(conceptsApiResponse.Broader) > 0 {
+		mappedConcept.BroaderConcepts = convertRelationship(conceptsApiResponse.Broader)
+	}
+	if len(conceptsApiResponse.Narrower) > 0 {
+		mappedConcept.NarrowerConcepts = convertRelationship(conceptsApiResponse.Narrower)
+	}
+	if len(conceptsApiResponse.Related) > 0 {
+		mappedConcept.RelatedConcepts = convertRelationship(conceptsApiResponse.Related)
+	}
+
+	return mappedConcept
+}
+
+// Check calls the good to go endpoint of public-concepts-api, reporting the state of the circuit breaker if any.
+func (api *ConceptsAPI) Check(ctx context.Context) (string, error) {
+	req, err := http.NewRequest("GET", api.conceptsURL+"/__gtg", nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := api.checkClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%v%s", err, api.breakerStatus())
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("health check returned a non-200 HTTP status: %v%s", resp.StatusCode, api.breakerStatus())
+	}
+	return "Public Concepts API is healthy" + api.breakerStatus(), nil
+}
+
+func (api *ConceptsAPI) breakerStatus() string {
+	if api.breaker == nil {
+		return ""
+	}
+	return fmt.Sprintf(", circuit breaker is %s", api.breaker.currentState())
+}
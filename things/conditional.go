@@ -0,0 +1,111 @@
+package things
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotModified is returned by a ConceptSource when the concept was not modified since the If-Modified-Since date
+// of the context, as when public-concepts-api answers a conditional request with 304 Not Modified.
+var ErrNotModified = errors.New("concept not modified since the requested date")
+
+type contextKey int
+
+const ifModifiedSinceKey contextKey = iota
+
+// withIfModifiedSince makes the requests to public-concepts-api made under the returned context conditional, or
+// unconditional again given a zero date.
+func withIfModifiedSince(ctx context.Context, since time.Time) context.Context {
+	if since.IsZero() && IfModifiedSince(ctx).IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, ifModifiedSinceKey, since)
+}
+
+// IfModifiedSince returns the date a ConceptSource may answer ErrNotModified for when the concept was not modified
+// since, or a zero time when the read is unconditional.
+func IfModifiedSince(ctx context.Context) time.Time {
+	since, _ := ctx.Value(ifModifiedSinceKey).(time.Time)
+	return since
+}
+
+// requestIfModifiedSince returns the If-Modified-Since date of the request, ignored when If-None-Match is present.
+func requestIfModifiedSince(r *http.Request) time.Time {
+	if r.Header.Get("If-None-Match") != "" {
+		return time.Time{}
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return time.Time{}
+	}
+	return since
+}
+
+// strongETag identifies a serialized representation by the hash of its bytes.
+func strongETag(body []byte) string {
+	sum := sha1.Sum(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// notModified tells whether the validators of the request match the representation the client already has.
+// If-None-Match takes precedence over If-Modified-Since, as per RFC 7232. Only GET requests are conditional.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != "GET" {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	since := requestIfModifiedSince(r)
+	if since.IsZero() || lastModified.IsZero() {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// writeRepresentation writes the JSON body with its validators, or a 304 when the client already has it.
+func writeRepresentation(w http.ResponseWriter, r *http.Request, body *bytes.Buffer, lastModified time.Time) {
+	etag := strongETag(body.Bytes())
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(r, etag, lastModified) {
+		writeNotModified(w)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+func writeNotModified(w http.ResponseWriter) {
+	w.Header().Del("Content-Type")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// latestModification returns the most recent modification date of the things, or a zero time when any of them is
+// unknown.
+func latestModification(things map[string]Concept) time.Time {
+	var latest time.Time
+	for _, thing := range things {
+		if thing.lastModified.IsZero() {
+			return time.Time{}
+		}
+		if thing.lastModified.After(latest) {
+			latest = thing.lastModified
+		}
+	}
+	return latest
+}
@@ -0,0 +1,224 @@
+package things
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const lastModifiedHeader = "Tue, 10 Oct 2017 10:00:00 GMT"
+
+// conditionalHTTPClient answers with a 304 when the request is not older than the concept, recording the last
+// If-Modified-Since header it received.
+type conditionalHTTPClient struct {
+	body            string
+	lastModified    string
+	ifModifiedSince string
+	calls           int
+}
+
+func (c *conditionalHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" {
+		c.ifModifiedSince = ims
+	}
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Last-Modified": []string{c.lastModified}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(c.body))),
+	}
+	since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	lastModified, _ := http.ParseTime(c.lastModified)
+	if err == nil && !lastModified.After(since) {
+		resp.StatusCode = http.StatusNotModified
+		resp.Body = ioutil.NopCloser(bytes.NewReader(nil))
+	}
+	return resp, nil
+}
+
+func serveThing(router *mux.Router, url string, header http.Header) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", url, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetThingETag(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	rr := serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Equal(t, strongETag(rr.Body.Bytes()), etag)
+	assert.Empty(t, rr.Header().Get("Last-Modified"), "public-concepts-api did not report a modification date")
+
+	rr = serveThing(router, url, http.Header{"If-None-Match": []string{`"stale", ` + etag}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+	assert.Empty(t, rr.Body.String())
+
+	rr = serveThing(router, url, http.Header{"If-None-Match": []string{`"stale"`}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+}
+
+func TestGetThingIfModifiedSince(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept, lastModified: lastModifiedHeader}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	rr := serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, lastModifiedHeader, rr.Header().Get("Last-Modified"))
+	assert.Empty(t, client.ifModifiedSince)
+
+	rr = serveThing(router, url, http.Header{"If-Modified-Since": []string{lastModifiedHeader}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, client.ifModifiedSince, "without a cached copy to answer a 304 with, the thing should be read unconditionally")
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, lastModifiedHeader, rr.Header().Get("Last-Modified"), "a 304 should carry the validators of a 200")
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+
+	rr = serveThing(router, url, http.Header{"If-Modified-Since": []string{"Mon, 09 Oct 2017 10:00:00 GMT"}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+}
+
+func TestGetThingIfModifiedSinceServedFromCache(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept, lastModified: lastModifiedHeader}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithConceptCache(10, time.Minute))
+	handler.RegisterHandlers(router)
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	serveThing(router, url, nil)
+	rr := serveThing(router, url, http.Header{"If-Modified-Since": []string{lastModifiedHeader}})
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestIfNoneMatchTakesPrecedenceOverIfModifiedSince(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept, lastModified: lastModifiedHeader}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+
+	rr := serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", http.Header{
+		"If-None-Match":     []string{`"stale"`},
+		"If-Modified-Since": []string{lastModifiedHeader},
+	})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, client.ifModifiedSince)
+}
+
+func TestGetThingsETag(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept, lastModified: lastModifiedHeader}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+	url := "/things?uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	rr := serveThing(router, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Equal(t, strongETag(rr.Body.Bytes()), etag)
+	assert.Equal(t, lastModifiedHeader, rr.Header().Get("Last-Modified"))
+
+	rr = serveThing(router, url, http.Header{"If-None-Match": []string{etag}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestPostThingsIsNotConditional(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/things", bytes.NewReader([]byte(`{"uuids":["6773e864-78ab-4051-abc2-f4e9ab423ebb"]}`)))
+	req.Header.Set("If-None-Match", "*")
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestGetThingIfModifiedSinceUsesRetainedCopy(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept, lastModified: lastModifiedHeader}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithConceptCache(10, time.Millisecond), WithStaleServing(0, time.Hour))
+	handler.RegisterHandlers(router)
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	rr := serveThing(router, url, nil)
+	etag := rr.Header().Get("ETag")
+	time.Sleep(5 * time.Millisecond)
+
+	rr = serveThing(router, url, http.Header{"If-Modified-Since": []string{lastModifiedHeader}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+	assert.Equal(t, lastModifiedHeader, rr.Header().Get("Last-Modified"))
+	assert.Equal(t, lastModifiedHeader, client.ifModifiedSince, "If-Modified-Since should be forwarded to public-concepts-api")
+	assert.Equal(t, 2, client.calls, "the expired copy should be used once public-concepts-api confirmed it is current")
+}
+
+func TestGetThingIfModifiedSinceWithoutCurrentCopy(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &conditionalHTTPClient{body: getCompleteThingAsConcept}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithConceptCache(10, time.Millisecond), WithStaleServing(0, time.Hour))
+	handler.RegisterHandlers(router)
+	url := "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb"
+
+	serveThing(router, url, nil)
+	time.Sleep(5 * time.Millisecond)
+	staleHits := handler.cache.staleHits.Count()
+
+	rr := serveThing(router, url, http.Header{"If-Modified-Since": []string{lastModifiedHeader}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, client.ifModifiedSince, "a copy without a modification date can't answer a 304")
+	assert.Equal(t, 2, client.calls, "the thing should be read once, unconditionally")
+	assert.Equal(t, staleHits, handler.cache.staleHits.Count())
+}
+
+func TestGetThingIfModifiedSinceRedirects(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	conditional := mock.MatchedBy(func(ctx context.Context) bool { return !IfModifiedSince(ctx).IsZero() })
+	unconditional := mock.MatchedBy(func(ctx context.Context) bool { return IfModifiedSince(ctx).IsZero() })
+	source := new(mockedSource)
+	source.On("Read", conditional, alternateUUID, []string(nil)).Return(Concept{}, false, ErrNotModified)
+	source.On("Read", unconditional, alternateUUID, []string(nil)).Return(Concept{ID: "http://api.ft.com/things/" + canonicalUUID}, true, nil)
+	router := sourceHandler(source)
+
+	rr := serveThing(router, "/things/"+alternateUUID, http.Header{"If-Modified-Since": []string{lastModifiedHeader}})
+
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code, "alternate uuids should still redirect")
+	assert.Equal(t, "/things/"+canonicalUUID, rr.Header().Get("Location"))
+}
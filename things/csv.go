@@ -0,0 +1,30 @@
+package things
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+var csvHeader = []string{"uuid", "id", "prefLabel", "directType", "shortLabel", "aliases", "error"}
+
+// encodeCSVThings writes a row per requested uuid, in the order they were requested, with the canonical id and the
+// labels of its thing. Uuids without a thing are reported in the error column.
+func encodeCSVThings(w io.Writer, result *ThingsResponse) error {
+	writer := csv.NewWriter(w)
+	writer.Write(csvHeader)
+	for _, uuid := range result.requested {
+		thing, found := result.Things[uuid]
+		switch {
+		case found:
+			writer.Write([]string{uuid, thing.ID, thing.PrefLabel, thing.DirectType, thing.ShortLabel,
+				strings.Join(thing.Aliases, "; "), ""})
+		case result.Errors[uuid] != "":
+			writer.Write([]string{uuid, "", "", "", "", "", result.Errors[uuid]})
+		default:
+			writer.Write([]string{uuid, "", "", "", "", "", "not found"})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,38 @@
+package things
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetThingsAsCSV(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things?partial=true&uuid="+secondCanonicalUUID+"&uuid="+canonicalUUID+"&uuid="+secondCanonicalUUID,
+		http.Header{"Accept": []string{"text/csv"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=UTF-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "uuid,id,prefLabel,directType,shortLabel,aliases,error\n"+
+		secondCanonicalUUID+",,,,,,not found\n"+
+		canonicalUUID+",http://api.ft.com/things/"+canonicalUUID+`,"The ""Onyx"" Pike",http://www.ft.com/ontology/Topic,Onyx,Bob; BOB2,`+"\n",
+		rr.Body.String())
+}
+
+func TestGetThingsAsCSVWithFormat(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things?format=csv&uuid="+canonicalUUID+"&uuid="+secondCanonicalUUID, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=UTF-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "uuid,id,prefLabel,directType,shortLabel,aliases,error\n"+
+		canonicalUUID+",http://api.ft.com/things/"+canonicalUUID+`,"The ""Onyx"" Pike",http://www.ft.com/ontology/Topic,Onyx,Bob; BOB2,`+"\n"+
+		secondCanonicalUUID+",,,,,,not found\n",
+		rr.Body.String(), "uuids not found should have a row even when partial results are not requested")
+}
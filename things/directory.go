@@ -0,0 +1,350 @@
+package things
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/neo-model-utils-go/mapper"
+)
+
+const (
+	broaderPredicate           = "http://www.w3.org/2004/02/skos/core#broader"
+	broaderTransitivePredicate = "http://www.w3.org/2004/02/skos/core#broaderTransitive"
+	narrowerPredicate          = "http://www.w3.org/2004/02/skos/core#narrower"
+	relatedPredicate           = "http://www.w3.org/2004/02/skos/core#related"
+)
+
+// DirectorySource is the ConceptSource serving the concepts described by the JSON files of a directory, loaded in
+// memory once, so that things can be served without public-concepts-api. Files either hold a concept as returned by
+// public-concepts-api or an aggregated concept with its sourceRepresentations, as in things/fixtures. Files which do
+// not describe a concept of a known type are ignored.
+type DirectorySource struct {
+	dir string
+	// concepts are keyed by canonical uuid and hold no relationships
+	concepts map[string]directoryConcept
+	// canonical maps the canonical and alternate uuids of the concepts to their canonical uuid
+	canonical map[string]string
+	// known describes the concepts relationships can point to, including the ones only referenced by a file
+	known map[string]BasicConcept
+
+	broader  map[string][]string
+	narrower map[string][]string
+	related  map[string][]string
+}
+
+type directoryConcept struct {
+	concept      ConceptApiResponse
+	lastModified time.Time
+}
+
+// aggregatedConcept is the format of the concepts of things/fixtures, broader concepts being listed by uuid in
+// broaderUUIDs, or parentUUIDs for brands, and related ones in relatedUUIDs.
+type aggregatedConcept struct {
+	PrefUUID       string   `json:"prefUUID"`
+	UUID           string   `json:"uuid"`
+	PrefLabel      string   `json:"prefLabel"`
+	Type           string   `json:"type"`
+	Aliases        []string `json:"aliases"`
+	DescriptionXML string   `json:"descriptionXML"`
+	ImageURL       string   `json:"_imageUrl"`
+	EmailAddress   string   `json:"emailAddress"`
+	FacebookPage   string   `json:"facebookPage"`
+	TwitterHandle  string   `json:"twitterHandle"`
+	ScopeNote      string   `json:"scopeNote"`
+	ShortLabel     string   `json:"shortLabel"`
+	IsDeprecated   bool     `json:"isDeprecated"`
+	aggregatedRelationships
+	SourceRepresentations []struct {
+		UUID string `json:"uuid"`
+		aggregatedRelationships
+	} `json:"sourceRepresentations"`
+	AlternativeIdentifiers struct {
+		UUIDs []string `json:"uuids"`
+	} `json:"alternativeIdentifiers"`
+}
+
+type aggregatedRelationships struct {
+	BroaderUUIDs []string `json:"broaderUUIDs"`
+	ParentUUIDs  []string `json:"parentUUIDs"`
+	RelatedUUIDs []string `json:"relatedUUIDs"`
+}
+
+// NewDirectorySource loads the concepts described by the .json files of dir, failing on files which are not JSON
+// or describing a concept already described by another file.
+func NewDirectorySource(dir string) (*DirectorySource, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DirectorySource{
+		dir:       dir,
+		concepts:  map[string]directoryConcept{},
+		canonical: map[string]string{},
+		known:     map[string]BasicConcept{},
+		broader:   map[string][]string{},
+		narrower:  map[string][]string{},
+		related:   map[string][]string{},
+	}
+	alternates := map[string]string{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		body, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		uuid, alternateUUIDs, err := s.load(body, file.ModTime())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", file.Name(), err)
+		}
+		if uuid == "" {
+			logger.Warnf("Ignoring %s, it does not describe a concept of a known type", file.Name())
+			continue
+		}
+		for _, alternate := range alternateUUIDs {
+			alternates[strings.ToLower(alternate)] = uuid
+		}
+	}
+
+	// canonical uuids take precedence over the alternate uuids of other concepts
+	for alternate, uuid := range alternates {
+		if _, found := s.canonical[alternate]; !found {
+			s.canonical[alternate] = uuid
+		}
+	}
+	s.broader = s.canonicalRelationships(s.broader)
+	s.related = s.canonicalRelationships(s.related)
+	for uuid, broader := range s.broader {
+		for _, b := range broader {
+			s.narrower[b] = append(s.narrower[b], uuid)
+		}
+	}
+	// related concepts are related both ways
+	inverse := map[string][]string{}
+	for uuid, related := range s.related {
+		for _, r := range related {
+			inverse[r] = append(inverse[r], uuid)
+		}
+	}
+	for uuid, related := range inverse {
+		s.related[uuid] = append(s.related[uuid], related...)
+	}
+
+	logger.Infof("Loaded %d concepts from %s", len(s.concepts), dir)
+	return s, nil
+}
+
+// load adds the concept described by the file body, returning its canonical and alternate uuids. The uuid is empty
+// when the file does not describe a concept.
+func (s *DirectorySource) load(body []byte, lastModified time.Time) (string, []string, error) {
+	var format struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &format); err != nil {
+		return "", nil, err
+	}
+
+	var concept ConceptApiResponse
+	var alternateUUIDs []string
+	if format.ID != "" {
+		decoded, err := decodeConceptApiResponse(body)
+		if err != nil {
+			return "", nil, err
+		}
+		concept = s.loadConceptsAPIFormat(decoded)
+	} else {
+		var aggregated aggregatedConcept
+		if err := json.Unmarshal(body, &aggregated); err != nil {
+			return "", nil, err
+		}
+		concept, alternateUUIDs = s.loadAggregatedFormat(aggregated)
+	}
+	if concept.Type == "" {
+		return "", nil, nil
+	}
+
+	uuid, _ := uuidFromID(concept.ID)
+	if _, found := s.concepts[uuid]; found {
+		return "", nil, fmt.Errorf("concept %s is described by another file", uuid)
+	}
+	s.concepts[uuid] = directoryConcept{concept: concept, lastModified: lastModified}
+	s.canonical[uuid] = uuid
+	s.known[uuid] = concept.BasicConcept
+	return uuid, alternateUUIDs, nil
+}
+
+// loadConceptsAPIFormat records the relationships of the concept and returns it without them.
+func (s *DirectorySource) loadConceptsAPIFormat(concept ConceptApiResponse) ConceptApiResponse {
+	uuid, _ := uuidFromID(concept.ID)
+	targets := func(relationships []Relationship) []string {
+		var uuids []string
+		for _, relationship := range relationships {
+			if target, ok := uuidFromID(relationship.Concept.ID); ok {
+				uuids = append(uuids, target)
+				if _, found := s.known[target]; !found {
+					s.known[target] = relationship.Concept
+				}
+			}
+		}
+		return uuids
+	}
+	s.broader[uuid] = append(s.broader[uuid], targets(concept.Broader)...)
+	for _, narrower := range targets(concept.Narrower) {
+		s.broader[narrower] = append(s.broader[narrower], uuid)
+	}
+	s.related[uuid] = append(s.related[uuid], targets(concept.Related)...)
+
+	concept.Broader, concept.Narrower, concept.Related = nil, nil, nil
+	return concept
+}
+
+// loadAggregatedFormat records the relationships of the concept and returns it in the format of public-concepts-api,
+// along with the uuids of its source representations.
+func (s *DirectorySource) loadAggregatedFormat(aggregated aggregatedConcept) (ConceptApiResponse, []string) {
+	uuid := strings.ToLower(aggregated.PrefUUID)
+	if uuid == "" {
+		uuid = strings.ToLower(aggregated.UUID)
+	}
+	typeURIs := mapper.TypeURIs([]string{aggregated.Type})
+	if !uuidRegexp.MatchString(uuid) || len(typeURIs) == 0 {
+		return ConceptApiResponse{}, nil
+	}
+
+	concept := ConceptApiResponse{
+		BasicConcept: BasicConcept{
+			ID:           ftThing + uuid,
+			Type:         typeURIs[len(typeURIs)-1],
+			PrefLabel:    aggregated.PrefLabel,
+			IsDeprecated: aggregated.IsDeprecated,
+		},
+		DescriptionXML: aggregated.DescriptionXML,
+		ImageURL:       aggregated.ImageURL,
+		ScopeNote:      aggregated.ScopeNote,
+		IsDeprecated:   aggregated.IsDeprecated,
+	}
+	for _, alias := range aggregated.Aliases {
+		concept.AlternativeLabels = append(concept.AlternativeLabels, TypedValue{Type: aliasLabelURI, Value: alias})
+	}
+	if aggregated.ShortLabel != "" {
+		concept.AlternativeLabels = append(concept.AlternativeLabels, TypedValue{Type: shortLabelURI, Value: aggregated.ShortLabel})
+	}
+	for _, account := range []TypedValue{
+		{Type: emailAddressURI, Value: aggregated.EmailAddress},
+		{Type: facebookPageURI, Value: aggregated.FacebookPage},
+		{Type: twitterURI, Value: aggregated.TwitterHandle},
+	} {
+		if account.Value != "" {
+			concept.Account = append(concept.Account, account)
+		}
+	}
+
+	relationships := []aggregatedRelationships{aggregated.aggregatedRelationships}
+	alternateUUIDs := aggregated.AlternativeIdentifiers.UUIDs
+	for _, source := range aggregated.SourceRepresentations {
+		relationships = append(relationships, source.aggregatedRelationships)
+		alternateUUIDs = append(alternateUUIDs, source.UUID)
+	}
+	for _, r := range relationships {
+		s.broader[uuid] = append(s.broader[uuid], lowerUUIDs(r.BroaderUUIDs)...)
+		s.broader[uuid] = append(s.broader[uuid], lowerUUIDs(r.ParentUUIDs)...)
+		s.related[uuid] = append(s.related[uuid], lowerUUIDs(r.RelatedUUIDs)...)
+	}
+	return concept, alternateUUIDs
+}
+
+func lowerUUIDs(uuids []string) []string {
+	lowered := make([]string, len(uuids))
+	for i, uuid := range uuids {
+		lowered[i] = strings.ToLower(uuid)
+	}
+	return lowered
+}
+
+// canonicalRelationships returns the relationships between the canonical uuids of the concepts.
+func (s *DirectorySource) canonicalRelationships(relationships map[string][]string) map[string][]string {
+	canonical := map[string][]string{}
+	for uuid, targets := range relationships {
+		for _, target := range targets {
+			canonical[s.canonicalUUID(uuid)] = append(canonical[s.canonicalUUID(uuid)], s.canonicalUUID(target))
+		}
+	}
+	return canonical
+}
+
+func (s *DirectorySource) canonicalUUID(uuid string) string {
+	if canonical, found := s.canonical[uuid]; found {
+		return canonical
+	}
+	return uuid
+}
+
+// Read returns the concept with the given canonical or alternate uuid, its ID being the one of the canonical concept.
+// Relationships to concepts neither loaded nor described by a loaded file are left out.
+func (s *DirectorySource) Read(ctx context.Context, uuid string, relationships []string) (Concept, bool, error) {
+	canonical, found := s.canonical[strings.ToLower(uuid)]
+	if !found {
+		return Concept{}, false, nil
+	}
+	loaded := s.concepts[canonical]
+
+	concept := loaded.concept
+	for _, relationship := range relationships {
+		switch relationship {
+		case "broader":
+			concept.Broader = append(concept.Broader, s.relationships(canonical, s.broader[canonical], broaderPredicate)...)
+		case "broaderTransitive":
+			concept.Broader = append(concept.Broader, s.relationships(canonical, s.broaderTransitive(canonical), broaderTransitivePredicate)...)
+		case "narrower":
+			concept.Narrower = s.relationships(canonical, s.narrower[canonical], narrowerPredicate)
+		case "related":
+			concept.Related = s.relationships(canonical, s.related[canonical], relatedPredicate)
+		}
+	}
+
+	thing := mapConcept(uuid, concept)
+	thing.lastModified = loaded.lastModified
+	return thing, true, nil
+}
+
+func (s *DirectorySource) relationships(uuid string, targets []string, predicate string) []Relationship {
+	var relationships []Relationship
+	seen := map[string]bool{uuid: true}
+	for _, target := range targets {
+		concept, found := s.known[target]
+		if !found || seen[target] {
+			continue
+		}
+		seen[target] = true
+		relationships = append(relationships, Relationship{Concept: concept, Predicate: predicate})
+	}
+	return relationships
+}
+
+// broaderTransitive returns the uuids of the concepts broader than the concept, directly or not, closest first.
+func (s *DirectorySource) broaderTransitive(uuid string) []string {
+	var transitive []string
+	seen := map[string]bool{uuid: true}
+	for queue := []string{uuid}; len(queue) > 0; queue = queue[1:] {
+		for _, broader := range s.broader[queue[0]] {
+			if !seen[broader] {
+				seen[broader] = true
+				transitive = append(transitive, broader)
+				queue = append(queue, broader)
+			}
+		}
+	}
+	return transitive
+}
+
+// Check reports how many concepts are served, the directory being loaded once and for all.
+func (s *DirectorySource) Check(ctx context.Context) (string, error) {
+	return fmt.Sprintf("Serving %d concepts loaded from %s", len(s.concepts), s.dir), nil
+}
@@ -0,0 +1,135 @@
+package things
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	onyxPikeUUID                  = "9a07c16f-def0-457d-a04a-57ba68ba1e00"
+	onyxPikeBroaderUUID           = "ba42b8d0-844f-4f2a-856c-5cbd863bf6bd"
+	onyxPikeBroaderTransitiveUUID = "a0ec2c50-1174-48f2-b804-d1f346bb7256"
+	onyxPikeRelatedUUID           = "ec20c787-8289-4cef-aee8-4d39e9563dc5"
+)
+
+func relatedIDs(things []Thing) []string {
+	var ids []string
+	for _, thing := range things {
+		ids = append(ids, thing.ID)
+	}
+	return ids
+}
+
+func TestDirectorySourceServesFixtures(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source, err := NewDirectorySource("fixtures")
+	assert.NoError(t, err)
+
+	thing, found, err := source.Read(context.Background(), onyxPikeUUID, nil)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "http://api.ft.com/things/"+onyxPikeUUID, thing.ID)
+	assert.Equal(t, "Onyx Pike", thing.PrefLabel)
+	assert.Equal(t, "http://www.ft.com/ontology/Topic", thing.DirectType)
+	assert.Equal(t, []string{"Bob", "BOB2"}, thing.Aliases)
+	assert.Equal(t, "Short Label", thing.ShortLabel)
+	assert.Equal(t, "email@email.com", thing.EmailAddress)
+	assert.Equal(t, "http://media.ft.com/brand.png", thing.ImageURL)
+	assert.Empty(t, thing.BroaderConcepts)
+	assert.False(t, thing.lastModified.IsZero())
+
+	_, found, err = source.Read(context.Background(), "3fc9fe3e-af8c-4f7f-961a-e5065392bb31", nil)
+	assert.NoError(t, err)
+	assert.False(t, found, "content is not a concept")
+
+	output, err := source.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Serving 9 concepts loaded from fixtures", output)
+}
+
+func TestDirectorySourceDerivesRelationships(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source, err := NewDirectorySource("fixtures")
+	assert.NoError(t, err)
+
+	thing, _, _ := source.Read(context.Background(), onyxPikeUUID, []string{"broader", "related"})
+	assert.Equal(t, []string{"http://api.ft.com/things/" + onyxPikeBroaderUUID}, relatedIDs(thing.BroaderConcepts))
+	assert.Equal(t, broaderPredicate, thing.BroaderConcepts[0].Predicate)
+	assert.Equal(t, "Onyx Pike Broader", thing.BroaderConcepts[0].PrefLabel)
+	assert.Equal(t, []string{"http://api.ft.com/things/" + onyxPikeRelatedUUID}, relatedIDs(thing.RelatedConcepts))
+
+	thing, _, _ = source.Read(context.Background(), onyxPikeUUID, []string{"broaderTransitive"})
+	assert.Equal(t, []string{
+		"http://api.ft.com/things/" + onyxPikeBroaderUUID,
+		"http://api.ft.com/things/" + onyxPikeBroaderTransitiveUUID,
+	}, relatedIDs(thing.BroaderConcepts))
+	assert.Equal(t, broaderTransitivePredicate, thing.BroaderConcepts[1].Predicate)
+
+	thing, _, _ = source.Read(context.Background(), onyxPikeBroaderUUID, []string{"narrower"})
+	assert.Equal(t, []string{"http://api.ft.com/things/" + onyxPikeUUID}, relatedIDs(thing.NarrowerConcepts))
+
+	thing, _, _ = source.Read(context.Background(), onyxPikeRelatedUUID, []string{"related"})
+	assert.Equal(t, []string{"http://api.ft.com/things/" + onyxPikeUUID}, relatedIDs(thing.RelatedConcepts),
+		"related concepts should be related both ways")
+
+	thing, _, _ = source.Read(context.Background(), "2d3e16e0-61cb-4322-8aff-3b01c59f4daa", []string{"broader"})
+	assert.Equal(t, []string{"http://api.ft.com/things/dbb0bdae-1f0c-11e4-b0cb-b2227cce2b54"}, relatedIDs(thing.BroaderConcepts),
+		"the parents of brands should be broader")
+}
+
+func TestDirectorySourceRedirectsAlternateUUIDs(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source, err := NewDirectorySource("fixtures")
+	assert.NoError(t, err)
+	router := sourceHandler(source)
+
+	rr := serveThing(router, "/things/4c4738cb-45df-43fe-ac7c-bab963b698ea", nil)
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/things/"+onyxPikeUUID, rr.Header().Get("Location"))
+
+	rr = serveThing(router, "/things/0a774476-cb80-4b9f-8dc3-fc59d6fba6e4", nil)
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/things/eac853f5-3859-4c08-8540-55e043719400", rr.Header().Get("Location"))
+
+	rr = serveThing(router, "/things/"+onyxPikeUUID, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+}
+
+func TestDirectorySourceLoadsConceptsAPIFormat(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	dir, err := ioutil.TempDir("", "concepts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "brussels-blog.json"), []byte(getConmpleteThingWithRelationAsConcept), 0644)
+	assert.NoError(t, err)
+
+	source, err := NewDirectorySource(dir)
+	assert.NoError(t, err)
+
+	thing, found, err := source.Read(context.Background(), "6773e864-78ab-4051-abc2-f4e9ab423ebb", []string{"related", "narrower"})
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NotEmpty(t, thing.RelatedConcepts)
+	assert.NotEmpty(t, thing.NarrowerConcepts)
+	assert.Empty(t, thing.BroaderConcepts, "relationships should only be returned when requested")
+}
+
+func TestDirectorySourceFailsOnInvalidFiles(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	dir, err := ioutil.TempDir("", "concepts")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"prefUUID":`), 0644)
+	assert.NoError(t, err)
+
+	_, err = NewDirectorySource(dir)
+	assert.EqualError(t, err, "failed to load broken.json: unexpected end of JSON input")
+}
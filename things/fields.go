@@ -0,0 +1,198 @@
+package things
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldset lists the JSON fields of the things to return, the fields of relationships selecting in turn the fields of
+// the related things. A nil fieldset selects all the fields, and so does a nil entry for a relationship.
+type fieldset map[string]fieldset
+
+var (
+	conceptFields = jsonFieldNames(reflect.TypeOf(Concept{}))
+	thingFields   = jsonFieldNames(reflect.TypeOf(Thing{}))
+)
+
+// jsonFieldNames maps the names of the JSON fields of the struct to their type.
+func jsonFieldNames(structType reflect.Type) map[string]reflect.Type {
+	names := map[string]reflect.Type{}
+	for i := 0; i < structType.NumField(); i++ {
+		if name := jsonFieldName(structType.Field(i)); name != "" {
+			names[name] = structType.Field(i).Type
+		}
+	}
+	return names
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// parseFields reads the fields query parameters, each being a comma separated list of fields like
+// prefLabel,types,broaderConcepts.prefLabel. It returns a nil fieldset when no fields are requested.
+func parseFields(values []string) (fieldset, error) {
+	var fields fieldset
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if fields == nil {
+				fields = fieldset{}
+			}
+
+			path := strings.Split(field, ".")
+			fieldType, found := conceptFields[path[0]]
+			switch {
+			case !found || len(path) > 2:
+				return nil, fmt.Errorf("unknown field %s", field)
+			case len(path) == 1:
+				fields[path[0]] = nil
+			default:
+				if _, found := thingFields[path[1]]; !found || fieldType != reflect.TypeOf([]Thing{}) {
+					return nil, fmt.Errorf("unknown field %s", field)
+				}
+				related, selected := fields[path[0]]
+				if selected && related == nil {
+					continue
+				}
+				if related == nil {
+					related = fieldset{}
+					fields[path[0]] = related
+				}
+				related[path[1]] = nil
+			}
+		}
+	}
+	return fields, nil
+}
+
+// selects reports whether the field is returned, the id always being.
+func (f fieldset) selects(name string) bool {
+	if f == nil || name == "id" {
+		return true
+	}
+	_, found := f[name]
+	return found
+}
+
+// applyTo returns a copy of the thing without the fields which are not selected, remembering them so that they are
+// left out of its JSON as well.
+func (f fieldset) applyTo(thing Concept) Concept {
+	if f == nil {
+		return thing
+	}
+	thing.fields = f
+	value := reflect.ValueOf(&thing).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name := jsonFieldName(value.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		if !f.selects(name) {
+			value.Field(i).Set(reflect.Zero(value.Field(i).Type()))
+			continue
+		}
+		if related, ok := value.Field(i).Interface().([]Thing); ok && f[name] != nil {
+			value.Field(i).Set(reflect.ValueOf(f[name].applyToRelated(related)))
+		}
+	}
+	return thing
+}
+
+// applyToBatch returns a copy of the batch result with the selected fields of its things, leaving the result itself
+// untouched for deciding the headers of the response.
+func (f fieldset) applyToBatch(result *ThingsResponse) *ThingsResponse {
+	if f == nil {
+		return result
+	}
+	selected := *result
+	selected.Things = make(map[string]Concept, len(result.Things))
+	for uuid, thing := range result.Things {
+		selected.Things[uuid] = f.applyTo(thing)
+	}
+	return &selected
+}
+
+// applyToRelated returns copies of the related things with the selected fields. Their predicate is kept, as RDF
+// representations link things with it, but left out of their JSON unless selected.
+func (f fieldset) applyToRelated(related []Thing) []Thing {
+	selected := make([]Thing, 0, len(related))
+	for _, thing := range related {
+		thing.fields = f
+		value := reflect.ValueOf(&thing).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			name := jsonFieldName(value.Type().Field(i))
+			if name != "" && name != "predicate" && !f.selects(name) {
+				value.Field(i).Set(reflect.Zero(value.Field(i).Type()))
+			}
+		}
+		selected = append(selected, thing)
+	}
+	return selected
+}
+
+// encode encodes the selected JSON fields of the struct in the order they are declared, without the empty ones
+// tagged omitempty.
+func (f fieldset) encode(value reflect.Value) ([]byte, error) {
+	var encoded bytes.Buffer
+	encoded.WriteByte('{')
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := jsonFieldName(field)
+		if name == "" || !f.selects(name) || (strings.HasSuffix(field.Tag.Get("json"), ",omitempty") && isEmptyJSON(value.Field(i))) {
+			continue
+		}
+		encodedField, err := json.Marshal(value.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if encoded.Len() > 1 {
+			encoded.WriteByte(',')
+		}
+		fmt.Fprintf(&encoded, "%q:", name)
+		encoded.Write(encodedField)
+	}
+	encoded.WriteByte('}')
+	return encoded.Bytes(), nil
+}
+
+// isEmptyJSON reports whether the value is left out of JSON objects by omitempty.
+func isEmptyJSON(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	}
+	return false
+}
+
+type conceptJSON Concept
+
+// MarshalJSON encodes the concept with the fields selected by a fields parameter, if any.
+func (c Concept) MarshalJSON() ([]byte, error) {
+	if c.fields == nil {
+		return json.Marshal(conceptJSON(c))
+	}
+	return c.fields.encode(reflect.ValueOf(c))
+}
+
+type thingJSON Thing
+
+// MarshalJSON encodes the related thing with the fields selected by a fields parameter, if any.
+func (t Thing) MarshalJSON() ([]byte, error) {
+	if t.fields == nil {
+		return json.Marshal(thingJSON(t))
+	}
+	return t.fields.encode(reflect.ValueOf(t))
+}
@@ -0,0 +1,110 @@
+package things
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := parseFields(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = parseFields([]string{""})
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = parseFields([]string{"prefLabel, types,broaderConcepts.prefLabel", "relatedConcepts.types,relatedConcepts"})
+	assert.NoError(t, err)
+	assert.Equal(t, fieldset{
+		"prefLabel":       nil,
+		"types":           nil,
+		"broaderConcepts": fieldset{"prefLabel": nil},
+		"relatedConcepts": nil,
+	}, fields)
+
+	for _, invalid := range []string{"label", "prefLabel.id", "broaderConcepts.aliases", "broaderConcepts.types.x", "lastModified"} {
+		_, err = parseFields([]string{invalid})
+		assert.EqualError(t, err, "unknown field "+invalid)
+	}
+}
+
+func TestGetThingWithFields(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things/"+canonicalUUID+"?fields=prefLabel,types,broaderConcepts.prefLabel", nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"id": "http://api.ft.com/things/`+canonicalUUID+`",
+		"prefLabel": "The \"Onyx\" Pike",
+		"types": ["http://www.ft.com/ontology/core/Thing", "http://www.ft.com/ontology/Topic"],
+		"broaderConcepts": [{"id": "http://api.ft.com/things/`+alternateUUID+`", "prefLabel": "Onyx Pike Broader"}]
+	}`, rr.Body.String())
+
+	rr = serveThing(router, "/things/"+canonicalUUID+"?fields=shortLabel", http.Header{"Accept": []string{"application/ld+json"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	node := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &node))
+	delete(node, "@context")
+	assert.Equal(t, map[string]interface{}{"@id": "http://api.ft.com/things/" + canonicalUUID, "shortLabel": "Onyx"}, node)
+}
+
+func TestGetThingsWithFields(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things?uuid="+canonicalUUID+"&fields=prefLabel", nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"things": {"`+canonicalUUID+`": {"id": "http://api.ft.com/things/`+canonicalUUID+`", "prefLabel": "The \"Onyx\" Pike"}}}`,
+		rr.Body.String())
+}
+
+func TestGetThingWithUnknownFields(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things/"+canonicalUUID+"?fields=prefLabel,label", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, `{"message":"unknown field label"}`, rr.Body.String())
+
+	rr = serveThing(router, "/things?uuid="+canonicalUUID+"&fields=broaderConcepts.aliases", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, `{"message":"unknown field broaderConcepts.aliases"}`, rr.Body.String())
+}
+
+func TestFieldsDoNotChangeCachePolicy(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource(), WithCachePolicy(testCachePolicy))
+
+	rr := serveThing(router, "/things/"+canonicalUUID+"?fields=prefLabel", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "max-age=60, public", rr.Header().Get("Cache-Control"),
+		"the cache policy of the direct type should apply even when it is not returned")
+
+	rr = serveThing(router, "/things?uuid="+canonicalUUID+"&fields=prefLabel", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "max-age=60, public", rr.Header().Get("Cache-Control"))
+	assert.NotContains(t, rr.Body.String(), "directType")
+}
+
+func TestFieldsKeepDeclaredOrder(t *testing.T) {
+	fields, err := parseFields([]string{"types,isDeprecated,aliases,prefLabel,apiUrl,broaderConcepts.prefLabel"})
+	assert.NoError(t, err)
+	thing := fields.applyTo(Concept{
+		ID: "http://api.ft.com/things/" + canonicalUUID, PrefLabel: "Pike", ScopeNote: "Fish",
+		BroaderConcepts: []Thing{{ID: "http://api.ft.com/things/" + alternateUUID, PrefLabel: "Fishes", Predicate: "broader"}},
+	})
+
+	encoded, err := json.Marshal(thing)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"http://api.ft.com/things/`+canonicalUUID+`","apiUrl":"","prefLabel":"Pike","types":null,`+
+		`"broaderConcepts":[{"id":"http://api.ft.com/things/`+alternateUUID+`","prefLabel":"Fishes"}]}`, string(encoded))
+}
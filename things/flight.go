@@ -0,0 +1,108 @@
+package things
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// flightGroup collapses concurrent lookups for the same key into a single call whose result,
+// including a not found outcome or an error, is shared by every waiting caller.
+//
+// The shared call runs with its own context, bounded by the deadline of the caller which started it, so that a
+// caller giving up does not fail the others. It is only cancelled once every caller waiting for it has given up.
+// Callers with a later deadline than the call in flight start a call of their own, which new callers then join.
+type flightGroup struct {
+	sync.Mutex
+	calls     map[string]*flightCall
+	coalesced metrics.Counter
+}
+
+type flightCall struct {
+	done     chan struct{}
+	cancel   context.CancelFunc
+	deadline time.Time
+	waiters  int
+	dups     int
+	concept  Concept
+	found    bool
+	err      error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{
+		calls:     make(map[string]*flightCall),
+		coalesced: metrics.GetOrRegisterCounter("things.upstream.coalesced", metrics.DefaultRegistry),
+	}
+}
+
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (Concept, bool, error)) (Concept, bool, error) {
+	g.Lock()
+	call, inFlight := g.calls[key]
+	if inFlight && call.outlives(ctx) {
+		call.dups++
+		g.coalesced.Inc(1)
+	} else {
+		call = g.start(ctx, key, fn)
+	}
+	call.waiters++
+	g.Unlock()
+
+	select {
+	case <-call.done:
+		return call.concept, call.found, call.err
+	case <-ctx.Done():
+		g.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			g.forget(key, call)
+			call.cancel()
+		}
+		g.Unlock()
+		return Concept{}, false, ctx.Err()
+	}
+}
+
+// start runs fn for the key in the background, must be called holding the lock.
+func (g *flightGroup) start(ctx context.Context, key string, fn func(ctx context.Context) (Concept, bool, error)) *flightCall {
+	var callCtx context.Context
+	var cancel context.CancelFunc
+	if deadline, ok := ctx.Deadline(); ok {
+		callCtx, cancel = context.WithDeadline(context.Background(), deadline)
+	} else {
+		callCtx, cancel = context.WithCancel(context.Background())
+	}
+
+	deadline, _ := callCtx.Deadline()
+	call := &flightCall{done: make(chan struct{}), cancel: cancel, deadline: deadline}
+	g.calls[key] = call
+
+	go func() {
+		defer close(call.done)
+		defer cancel()
+		call.concept, call.found, call.err = fn(callCtx)
+
+		g.Lock()
+		g.forget(key, call)
+		g.Unlock()
+	}()
+	return call
+}
+
+// outlives reports whether the call is given at least as long as the context to complete.
+func (call *flightCall) outlives(ctx context.Context) bool {
+	if call.deadline.IsZero() {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && !call.deadline.Before(deadline)
+}
+
+// forget stops sharing the call with new callers, must be called holding the lock.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
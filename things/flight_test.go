@@ -0,0 +1,187 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flightResult struct {
+	concept Concept
+	found   bool
+	err     error
+}
+
+// runCoalesced starts callers concurrent lookups for the same key, only releasing the upstream call once
+// every caller has joined the one in flight.
+func runCoalesced(t *testing.T, callers int, concept Concept, found bool, err error) ([]flightResult, int) {
+	group := newFlightGroup()
+	release := make(chan struct{})
+	var calls int
+	fn := func(ctx context.Context) (Concept, bool, error) {
+		calls++
+		<-release
+		return concept, found, err
+	}
+
+	results := make([]flightResult, callers)
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			c, f, e := group.do(context.Background(), "key", fn)
+			results[i] = flightResult{c, f, e}
+		}(i)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		group.Lock()
+		call, inFlight := group.calls["key"]
+		joined := inFlight && call.dups == callers-1
+		group.Unlock()
+		if joined {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("callers did not join the in-flight call")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	return results, calls
+}
+
+func TestFlightGroupSharesResult(t *testing.T) {
+	results, calls := runCoalesced(t, 5, Concept{ID: canonicalUUID}, true, nil)
+
+	assert.Equal(t, 1, calls)
+	for _, result := range results {
+		assert.True(t, result.found)
+		assert.NoError(t, result.err)
+		assert.Equal(t, canonicalUUID, result.concept.ID)
+	}
+}
+
+func TestFlightGroupSharesNotFound(t *testing.T) {
+	results, calls := runCoalesced(t, 3, Concept{}, false, nil)
+
+	assert.Equal(t, 1, calls)
+	for _, result := range results {
+		assert.False(t, result.found)
+		assert.NoError(t, result.err)
+	}
+}
+
+func TestFlightGroupSharesError(t *testing.T) {
+	upstreamErr := errors.New("upstream failure")
+	results, calls := runCoalesced(t, 3, Concept{}, false, upstreamErr)
+
+	assert.Equal(t, 1, calls)
+	for _, result := range results {
+		assert.Equal(t, upstreamErr, result.err)
+	}
+}
+
+func TestFlightGroupDoesNotShareCompletedCalls(t *testing.T) {
+	group := newFlightGroup()
+	var calls int
+	fn := func(ctx context.Context) (Concept, bool, error) {
+		calls++
+		return Concept{}, true, nil
+	}
+
+	group.do(context.Background(), "key", fn)
+	group.do(context.Background(), "key", fn)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestFlightGroupCancelsCallOnceAllCallersGaveUp(t *testing.T) {
+	group := newFlightGroup()
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	fn := func(ctx context.Context) (Concept, bool, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return Concept{}, false, ctx.Err()
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	secondCtx, cancelSecond := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() {
+		_, _, err := group.do(firstCtx, "key", fn)
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, _, err := group.do(secondCtx, "key", fn)
+		errs <- err
+	}()
+	for {
+		group.Lock()
+		joined := group.calls["key"].dups == 1
+		group.Unlock()
+		if joined {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelFirst()
+	assert.Equal(t, context.Canceled, <-errs)
+	select {
+	case <-cancelled:
+		t.Fatal("shared call should not be cancelled while a caller is still waiting for it")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancelSecond()
+	assert.Equal(t, context.Canceled, <-errs)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("shared call should be cancelled once every caller gave up")
+	}
+}
+
+func TestFlightGroupDoesNotShareCallsWithEarlierDeadlines(t *testing.T) {
+	group := newFlightGroup()
+	started := make(chan struct{})
+	var calls int
+	fn := func(ctx context.Context) (Concept, bool, error) {
+		calls++
+		if calls == 1 {
+			close(started)
+			<-ctx.Done()
+			return Concept{}, false, ctx.Err()
+		}
+		return Concept{PrefLabel: "label"}, true, nil
+	}
+
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	longCtx, cancelLong := context.WithTimeout(context.Background(), time.Second)
+	defer cancelLong()
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := group.do(shortCtx, "key", fn)
+		errs <- err
+	}()
+	<-started
+
+	concept, found, err := group.do(longCtx, "key", fn)
+	assert.NoError(t, err, "a caller with a later deadline should not get the deadline error of the call in flight")
+	assert.True(t, found)
+	assert.Equal(t, "label", concept.PrefLabel)
+	assert.Equal(t, context.DeadlineExceeded, <-errs)
+	assert.Equal(t, 2, calls)
+}
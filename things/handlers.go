@@ -1,14 +1,16 @@
 package things
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
-	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"io/ioutil"
 
@@ -22,8 +24,6 @@ import (
 	gouuid "github.com/satori/go.uuid"
 )
 
-var CacheControlHeader string
-
 const (
 	validUUID       = "([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$"
 	shortLabelURI   = "http://www.ft.com/ontology/shortLabel"
@@ -33,6 +33,11 @@ const (
 	twitterURI      = "http://www.ft.com/ontology/twitterHandle"
 	thingsApiUrl    = "http://api.ft.com/things/"
 	ftThing         = "http://www.ft.com/thing/"
+
+	maxBatchBodyBytes = 1 << 20
+
+	warningStale              = `110 - "Response is Stale"`
+	warningRevalidationFailed = `111 - "Revalidation Failed"`
 )
 
 var brandPredicateMap = map[string]string{
@@ -45,21 +50,174 @@ type HttpClient interface {
 }
 
 type ThingsHandler struct {
-	client      HttpClient
-	conceptsURL string
+	source ConceptSource
+	// conceptsAPI is the source when reading from public-concepts-api, which the upstream options configure
+	conceptsAPI *ConceptsAPI
+	cache       *conceptCache
+	notFound    *conceptCache
+	flight      *flightGroup
+	timeout     time.Duration
+	cachePolicy CachePolicy
+	purgeToken  string
+	warmUp      *warmUp
+
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+
+	maxRedirects     int
+	maxBatchSize     int
+	batchConcurrency int
+}
+
+// Option configures the optional behaviour of a ThingsHandler.
+type Option func(*ThingsHandler)
+
+// WithConceptCache keeps up to size concepts fetched from public-concepts-api in memory for the given ttl.
+// A non positive size or ttl leaves the cache disabled.
+func WithConceptCache(size int, ttl time.Duration) Option {
+	return func(h *ThingsHandler) {
+		if size > 0 && ttl > 0 {
+			h.cache = newConceptCache(size, ttl)
+		}
+	}
+}
+
+// WithNegativeCache remembers for the given ttl the uuids public-concepts-api found no concept for, up to size
+// uuids and relationships combinations. A non positive size or ttl leaves the negative cache disabled.
+func WithNegativeCache(size int, ttl time.Duration) Option {
+	return func(h *ThingsHandler) {
+		if size > 0 && ttl > 0 {
+			h.notFound = newNotFoundCache(size, ttl)
+		}
+	}
+}
+
+// WithCircuitBreaker stops calling public-concepts-api after failureThreshold consecutive failures,
+// failing fast until openTimeout has elapsed and a probe request succeeds. A non positive threshold
+// leaves the circuit breaker disabled. It only applies to handlers reading from public-concepts-api.
+func WithCircuitBreaker(failureThreshold int, openTimeout time.Duration) Option {
+	return func(h *ThingsHandler) {
+		if failureThreshold > 0 && h.conceptsAPI != nil {
+			h.conceptsAPI.breaker = newCircuitBreakerClient(h.conceptsAPI.client, failureThreshold, openTimeout)
+			h.conceptsAPI.client = h.conceptsAPI.breaker
+		}
+	}
+}
+
+// WithRetries retries failed GET requests for concepts according to the given policy. It only applies to handlers
+// reading from public-concepts-api.
+func WithRetries(policy RetryPolicy) Option {
+	return func(h *ThingsHandler) {
+		if h.conceptsAPI != nil {
+			h.conceptsAPI.retries = policy
+		}
+	}
+}
+
+// WithMaxBatchSize limits the number of uuids which can be requested in a single batch operation.
+// A non positive size leaves batches unlimited.
+func WithMaxBatchSize(size int) Option {
+	return func(h *ThingsHandler) {
+		h.maxBatchSize = size
+	}
+}
+
+// WithBatchConcurrency limits the number of things fetched concurrently for a single batch operation.
+// A non positive limit fetches all the requested things concurrently.
+func WithBatchConcurrency(limit int) Option {
+	return func(h *ThingsHandler) {
+		h.batchConcurrency = limit
+	}
 }
 
-func NewHandler(client HttpClient, conceptsURL string) ThingsHandler {
-	return ThingsHandler{
-		client,
-		conceptsURL,
+// WithUpstreamConcurrency caps the number of requests in flight to public-concepts-api across all the requests
+// served by the handler. Requests wait for up to queueTimeout for a free slot before failing. A non positive cap
+// leaves requests unlimited. It only applies to handlers reading from public-concepts-api.
+func WithUpstreamConcurrency(maxInFlight int, queueTimeout time.Duration) Option {
+	return func(h *ThingsHandler) {
+		if maxInFlight > 0 && h.conceptsAPI != nil {
+			h.conceptsAPI.client = newLimitingClient(h.conceptsAPI.client, maxInFlight, queueTimeout)
+		}
 	}
 }
 
+// WithRequestTimeout bounds the time spent getting things for a single request, abandoning the requests to
+// public-concepts-api still in flight once it has elapsed. A non positive timeout leaves requests unbounded.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(h *ThingsHandler) {
+		h.timeout = timeout
+	}
+}
+
+// WithStaleServing keeps cached concepts for a while after they expired. A concept expired for less than
+// staleWhileRevalidate is served straight away while being refreshed in the background, and one expired for less
+// than staleIfError is served when public-concepts-api fails to return a fresh one. It requires WithConceptCache.
+func WithStaleServing(staleWhileRevalidate time.Duration, staleIfError time.Duration) Option {
+	return func(h *ThingsHandler) {
+		h.staleWhileRevalidate = staleWhileRevalidate
+		h.staleIfError = staleIfError
+	}
+}
+
+// WithCachePolicy sets the Cache-Control headers of the responses according to the given policy.
+func WithCachePolicy(policy CachePolicy) Option {
+	return func(h *ThingsHandler) {
+		h.cachePolicy = policy
+	}
+}
+
+// NewHandler returns a handler reading concepts from public-concepts-api at conceptsURL with the given client.
+func NewHandler(client HttpClient, conceptsURL string, options ...Option) ThingsHandler {
+	return NewSourceHandler(NewConceptsAPI(client, conceptsURL), options...)
+}
+
+// NewSourceHandler returns a handler reading concepts from the given source.
+func NewSourceHandler(source ConceptSource, options ...Option) ThingsHandler {
+	h := ThingsHandler{
+		source: source,
+		flight: newFlightGroup(),
+	}
+	h.conceptsAPI, _ = source.(*ConceptsAPI)
+	for _, option := range options {
+		option(&h)
+	}
+	if h.cache != nil {
+		h.cache.retain = h.staleWhileRevalidate
+		if h.staleIfError > h.cache.retain {
+			h.cache.retain = h.staleIfError
+		}
+	}
+	return h
+}
+
+// requestContext returns the context getting things for the request should be bound to. It is cancelled when the
+// client goes away or when the configured request timeout elapses.
+func (h *ThingsHandler) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return h.boundedContext(r.Context())
+}
+
+func (h *ThingsHandler) boundedContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if h.timeout > 0 {
+		return context.WithTimeout(parent, h.timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+// contextError returns the error of a done context, treating a context past its deadline as done even before its
+// timer fired: shared lookups run against their own timer and may give up slightly earlier than the request.
+func contextError(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return ctx.Err()
+}
+
 func (h *ThingsHandler) RegisterHandlers(router *mux.Router) {
 	logger.Info("Registering handlers")
 	router.HandleFunc("/things/{uuid}", h.GetThing).Methods("GET")
 	router.HandleFunc("/things", h.GetThings).Methods("GET")
+	router.HandleFunc("/things", h.PostThings).Methods("POST")
+	h.registerPurgeHandlers(router)
 }
 
 func (h *ThingsHandler) HealthCheck() fthealth.Check {
@@ -76,12 +234,15 @@ func (h *ThingsHandler) HealthCheck() fthealth.Check {
 
 // GetThing handler directly returns the concept/thing if it's a canonical
 // or provides redirect URL via Location http header within the response.
+// Chains of alternate uuids are followed up to the configured number of redirects.
+// Clients asking to resolve the canonical thing get it directly instead of a redirect.
 func (rh *ThingsHandler) GetThing(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	uuid := vars["uuid"]
 	transID := transactionidutils.GetTransactionIDFromRequest(r)
 	relationships := r.URL.Query()["showRelationship"]
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Vary", "Accept, "+resolveCanonicalHeader)
 
 	if uuid == "" {
 		http.Error(w, "uuid required", http.StatusBadRequest)
@@ -93,64 +254,90 @@ func (rh *ThingsHandler) GetThing(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	thing, found, err := rh.getThingViaConceptsApi(uuid, relationships, transID)
+	fields, err := parseFields(r.URL.Query()["fields"])
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		msg := fmt.Sprintf(`{"message":"Error getting thing with uuid %s, err=%s"}`, uuid, err.Error())
-		w.Write([]byte(msg))
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"message":"%v"}`, err)))
 		return
 	}
-	if !found {
+
+	ctx, cancel := rh.requestContext(r)
+	defer cancel()
+	ctx = withIfModifiedSince(ctx, requestIfModifiedSince(r))
+
+	canonical := resolveCanonicalRequested(r)
+	maxFetches, mode := rh.redirectsFollowed(), resolveRedirect
+	if canonical {
+		maxFetches, mode = maxFetches+1, resolveCanonical
+	}
+
+	res, err := rh.resolveThing(ctx, uuid, relationships, transID, maxFetches, mode)
+	if err != nil {
+		writeThingError(w, uuid, err)
+		return
+	}
+	if !res.found {
+		setCacheControl(w, rh.cachePolicy.header("", http.StatusNotFound))
 		w.WriteHeader(http.StatusNotFound)
 		msg := fmt.Sprintf(`{"message":"No thing found with uuid %s."}`, uuid)
 		w.Write([]byte(msg))
 		return
 	}
-
-	//if the request was not made for the canonical, but an alternate uuid: redirect
-	if !strings.Contains(thing.ID, uuid) {
-		validRegexp := regexp.MustCompile(validUUID)
-		canonicalUUID := validRegexp.FindString(thing.ID)
-		redirectURL := strings.Replace(r.URL.String(), uuid, canonicalUUID, 1)
-		w.Header().Set("Location", redirectURL)
+	thing := res.thing
+	markStale(w, thing.staleWarning)
+
+	//if the request was not made for the canonical, but an alternate uuid: redirect to the end of the chain, unless
+	//the client asked for the canonical thing itself and it was reached
+	if canonical && res.resolved {
+		setCanonicalHeaders(w, r, uuid, res)
+	} else if res.redirected() {
+		w.Header().Set("Location", thingURL(r.URL, res.target()))
+		w.Header().Set("X-Redirect-Chain", formatRedirectChain(res.chain))
+		setCacheControl(w, rh.cachePolicy.header(thing.DirectType, http.StatusMovedPermanently))
 		w.WriteHeader(http.StatusMovedPermanently)
 		return
 	}
 
-	w.Header().Set("Cache-Control", CacheControlHeader)
-	w.WriteHeader(http.StatusOK)
-
-	if err = json.NewEncoder(w).Encode(thing); err != nil {
+	representation := negotiateRepresentation(r, thingRepresentations)
+	var body bytes.Buffer
+	if err = representation.encodeThing(&body, fields.applyTo(thing)); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		msg := fmt.Sprintf(`{"message":"Error parsing thing with uuid %s, err=%s"}`, uuid, err.Error())
 		w.Write([]byte(msg))
+		return
 	}
+
+	w.Header().Set("Content-Type", representation.contentType)
+	setCacheControl(w, rh.cachePolicy.header(thing.DirectType, http.StatusOK))
+	writeRepresentation(w, r, &body, thing.lastModified)
 }
 
 // GetThings handler provides a batch like functionality, quite similar to single get endpoint.
-// Implementation schedules a new go routine for every requested "thing" uuid, wait for the results and returns
-// the aggregated results to caller.
+// Implementation schedules a go routine for every requested "thing" uuid, up to the configured batch concurrency,
+// wait for the results and returns the aggregated results to caller.
 //
 // Non canonical uuid handling:
 //
 // 	Implementation slightly deviates from the single get endpoint for non canonical uuids.
 // 	It tries to resolve the canonical uuid/node itself instead of providing a reference url but strictly stops
-// 	if indirection dept is more than one level.
+// 	if indirection dept is more than the configured number of redirects. The chains of uuids traversed are
+// 	returned as a map of ["redirects":{[uuid:[uuid]]}].
 //
 // Error handling:
 //
 // 	In case of any error for any given uuid, implementation immediately returns with the error without waiting for the
 // 	in-flight queries to be finished.
+// 	When partial results are requested with the partial=true query parameter, implementation waits for all the
+// 	queries instead and returns the things it resolved alongside the errors and the not found uuids.
 //
 // Response structure:
 //
 // 	Instead of returning an array of things, we are returning/serializing a map of ["things":{[uuid:Thing]}]. Reason behind this
 // 	is simply to provide a convenient way to the caller for making the correlation between requested uuids with respect to
 // 	found things. Since we are handling the resolution of non canonical uuids, returned thing payloads may not have the same
-// 	requested/associated uuid.
+// 	requested/associated uuid. Partial results add a map of ["errors":{[uuid:message]}] and a ["notFound":[uuid]] list.
 func (rh *ThingsHandler) GetThings(w http.ResponseWriter, r *http.Request) {
 	queryParams := r.URL.Query()
-	transID := transactionidutils.GetTransactionIDFromRequest(r)
 	relationships := queryParams["showRelationship"]
 	uuids := queryParams["uuid"]
 
@@ -162,123 +349,305 @@ func (rh *ThingsHandler) GetThings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rh.getThings(w, r, uuids, relationships)
+}
+
+// PostThings handler provides the same functionality as GetThings for batches too large to fit in a URL,
+// reading the requested uuids and relationships from a JSON body like {"uuids":[...],"showRelationship":[...]}.
+func (rh *ThingsHandler) PostThings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBatchBodyBytes+1))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"message":"Error reading the request body, err=%s"}`, err.Error())))
+		return
+	}
+	if len(body) > maxBatchBodyBytes {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(fmt.Sprintf(`{"message":"request body should not be larger than %d bytes"}`, maxBatchBodyBytes)))
+		return
+	}
+
+	batch := ThingsRequest{}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"request body should be a JSON object like {\"uuids\":[...],\"showRelationship\":[...]}"}`))
+		return
+	}
+
+	if len(batch.UUIDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"at least one uuid should be provided for batch operations"}`))
+		return
+	}
+
+	rh.getThings(w, r, batch.UUIDs, batch.Relationships)
+}
+
+func (rh *ThingsHandler) getThings(w http.ResponseWriter, r *http.Request, uuids []string, relationships []string) {
+	transID := transactionidutils.GetTransactionIDFromRequest(r)
+	w.Header().Set("Vary", "Accept")
+
+	if rh.maxBatchSize > 0 && len(uuids) > rh.maxBatchSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(fmt.Sprintf(`{"message":"at most %d uuids can be requested in a single batch operation"}`, rh.maxBatchSize)))
+		return
+	}
+
 	if err := validateUUID(uuids...); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte(fmt.Sprintf(`{"message":"%v"}`, err)))
 		return
 	}
 
+	fields, fieldsErr := parseFields(r.URL.Query()["fields"])
+	if fieldsErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"message":"%v"}`, fieldsErr)))
+		return
+	}
+
+	representation, formatErr := batchRepresentation(r)
+	if formatErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"message":"%v"}`, formatErr)))
+		return
+	}
+
+	partial := r.URL.Query().Get("partial") == "true"
+
+	workers := len(uuids)
+	if rh.batchConcurrency > 0 && rh.batchConcurrency < workers {
+		workers = rh.batchConcurrency
+	}
+
+	// cancels the remaining work when giving up on the first error
+	ctx, cancel := rh.requestContext(r)
+	defer cancel()
+
+	uuidCh := make(chan string, len(uuids))
+	for _, uuid := range uuids {
+		uuidCh <- uuid
+	}
+	close(uuidCh)
+
 	var wg sync.WaitGroup
-	uctCh := make(chan *uuidConceptTuple)
-	errCh := make(chan *uuidErrorTuple)
+	// buffered so that goroutines never block on sending once aggregation has given up
+	resultCh := make(chan *uuidResultTuple, len(uuids))
 
 	// fill up the sync bucket
-	wg.Add(len(uuids))
+	wg.Add(workers)
 
 	// start getting things
-	for _, uuid := range uuids {
-		go rh.getChanneledThing(uuid, relationships, transID, uctCh, errCh, &wg)
+	for i := 0; i < workers; i++ {
+		go rh.getChanneledThings(ctx, uuidCh, relationships, transID, resultCh, &wg)
 	}
 
 	// start watching the sync bucket and close the channel
-	go closeOnDone(uctCh, &wg)
+	go closeOnDone(resultCh, &wg)
+
+	if representation.streamThing != nil {
+		streamChanneledThings(w, representation, resultCh, partial, fields)
+		return
+	}
 
 	// synchronize/wait for the results
-	things, err := aggregateChanneledThings(uctCh, errCh)
+	result, err := aggregateChanneledThings(uuids, resultCh, partial)
 
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		msg := fmt.Sprintf(`{"message":"Error getting thing with uuid %s, err=%s"}`, err.uuid, err.err.Error())
+		writeThingError(w, err.uuid, err.err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := representation.encodeThings(&body, fields.applyToBatch(result)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		msg := fmt.Sprintf(`{"message":"Error marshalling the result %v, err=%s"}`, result, err.Error())
 		w.Write([]byte(msg))
 		return
 	}
 
-	w.Header().Set("Cache-Control", CacheControlHeader)
-	w.WriteHeader(http.StatusOK)
+	for _, thing := range result.Things {
+		if thing.staleWarning != "" {
+			markStale(w, thing.staleWarning)
+			break
+		}
+	}
+	w.Header().Set("Content-Type", representation.contentType)
+	setCacheControl(w, rh.cachePolicy.batchHeader(result))
+	writeRepresentation(w, r, &body, latestModification(result.Things))
+}
 
-	result := make(map[string]map[string]Concept)
-	result["things"] = things
+// markStale flags a response built from a concept served stale from the cache.
+func markStale(w http.ResponseWriter, warning string) {
+	if warning != "" {
+		w.Header().Set("Warning", warning)
+		w.Header().Set("X-Stale", "true")
+	}
+}
 
-	if err := json.NewEncoder(w).Encode(result); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		msg := fmt.Sprintf(`{"message":"Error marshalling the result %s, err=%s"}`, w, err.Error())
+// writeThingError reports the failure to get a thing with the HTTP status matching the upstream failure,
+// defaulting to 503.
+func writeThingError(w http.ResponseWriter, uuid string, err error) {
+	status := http.StatusServiceUnavailable
+	if upstreamErr, ok := err.(*UpstreamError); ok {
+		status = upstreamErr.Status
+		if upstreamErr.RetryAfter != "" {
+			w.Header().Set("Retry-After", upstreamErr.RetryAfter)
+		}
+	}
+	w.WriteHeader(status)
+	if err == ErrCircuitOpen {
+		msg := fmt.Sprintf(`{"message":"Public concepts API is unavailable, not getting thing with uuid %s until it recovers"}`, uuid)
 		w.Write([]byte(msg))
+		return
 	}
+	msg := fmt.Sprintf(`{"message":"Error getting thing with uuid %s, err=%s"}`, uuid, err.Error())
+	w.Write([]byte(msg))
 }
 
-func (rh *ThingsHandler) getChanneledThing(uuid string, relationships []string, transID string, uctCh chan *uuidConceptTuple,
-	errCh chan *uuidErrorTuple, wg *sync.WaitGroup) {
+// getChanneledThings is a batch worker getting things for the uuids it receives until the channel is closed.
+// Once the context is done the remaining uuids are reported as failed without calling public-concepts-api.
+func (rh *ThingsHandler) getChanneledThings(ctx context.Context, uuidCh chan string, relationships []string, transID string,
+	resultCh chan *uuidResultTuple, wg *sync.WaitGroup) {
 
 	defer wg.Done()
-	thing, found, err := rh.getThingViaConceptsApi(uuid, relationships, transID)
+	for uuid := range uuidCh {
+		if err := contextError(ctx); err != nil {
+			resultCh <- &uuidResultTuple{uuid: uuid, err: classifyTransportError(err)}
+			continue
+		}
+		rh.getChanneledThing(ctx, uuid, relationships, transID, resultCh)
+	}
+}
+
+func (rh *ThingsHandler) getChanneledThing(ctx context.Context, uuid string, relationships []string, transID string,
+	resultCh chan *uuidResultTuple) {
+
+	res, err := rh.resolveThing(ctx, uuid, relationships, transID, rh.redirectsFollowed()+1, resolveStrict)
 
 	if err != nil {
-		errCh <- &uuidErrorTuple{uuid, err}
+		resultCh <- &uuidResultTuple{uuid: uuid, err: err}
 		return
 	}
 
-	if !found {
+	if !res.found {
+		if res.redirected() {
+			logger.Errorf("Referenced canonical uuid : %s is missing in graph store for %s, possible data inconsistency",
+				res.target(), uuid)
+		}
+		resultCh <- &uuidResultTuple{uuid: uuid}
+		return
+	}
+
+	if !res.resolved {
+		logger.Warnf("More than %d levels of indirection to canonical node for uuid: %s, giving up traversing (%s)",
+			rh.redirectsFollowed(), uuid, formatRedirectChain(res.chain))
+		resultCh <- &uuidResultTuple{uuid: uuid}
 		return
 	}
 
-	if !strings.Contains(thing.ID, uuid) {
-		validRegexp := regexp.MustCompile(validUUID)
+	tuple := &uuidResultTuple{uuid: uuid, concept: res.thing, found: true}
+	if res.redirected() {
+		tuple.chain = res.chain
+	}
+	resultCh <- tuple
+}
 
-		canonicalUUID := validRegexp.FindString(thing.ID)
-		thing, found, err = rh.getThingViaConceptsApi(canonicalUUID, relationships, transID)
+// aggregateChanneledThings collects the results of every requested uuid. Unless partial results are requested it
+// gives up on the first error, leaving the remaining results in the buffered channel.
+func aggregateChanneledThings(uuids []string, resultCh chan *uuidResultTuple, partial bool) (*ThingsResponse, *uuidResultTuple) {
+	result := &ThingsResponse{Things: make(map[string]Concept)}
+	notFound := make(map[string]bool)
 
-		if err != nil {
-			errCh <- &uuidErrorTuple{uuid, err}
-			return
+	for tuple := range resultCh {
+		switch {
+		case tuple.err != nil && !partial:
+			return nil, tuple
+		case tuple.err != nil:
+			if result.Errors == nil {
+				result.Errors = make(map[string]string)
+			}
+			result.Errors[tuple.uuid] = tuple.err.Error()
+		case tuple.found:
+			result.Things[tuple.uuid] = tuple.concept
+			if tuple.chain != nil {
+				if result.Redirects == nil {
+					result.Redirects = make(map[string][]string)
+				}
+				result.Redirects[tuple.uuid] = tuple.chain
+			}
+		case partial:
+			notFound[tuple.uuid] = true
 		}
+	}
 
-		if !found {
-			logger.Errorf("Referenced canonical uuid : %s is missing in graph store for %s, possible data inconsistency",
-				canonicalUUID, uuid)
-			return
+	requested := make(map[string]bool)
+	for _, uuid := range uuids {
+		if !requested[uuid] {
+			result.requested = append(result.requested, uuid)
+			requested[uuid] = true
 		}
-
-		if !strings.Contains(thing.ID, canonicalUUID) {
-			// there should be one level of indirection to the canonical node
-			logger.Warnf("Multiple level of indirection to canonical node for uuid: %s, giving up traversing", uuid)
-			return
+		if notFound[uuid] {
+			result.NotFound = append(result.NotFound, uuid)
+			delete(notFound, uuid)
 		}
 	}
-
-	uctCh <- &uuidConceptTuple{uuid, thing}
+	return result, nil
 }
 
-func aggregateChanneledThings(uctCh chan *uuidConceptTuple, errCh chan *uuidErrorTuple) (map[string]Concept, *uuidErrorTuple) {
-	things := make(map[string]Concept)
+// streamChanneledThings writes the result of every requested uuid as soon as it is known, leaving out the uuids not
+// found unless partial results are requested. Otherwise the first error is answered with its HTTP status when nothing
+// was written yet, and ends the stream when some things were.
+func streamChanneledThings(w http.ResponseWriter, representation representation, resultCh chan *uuidResultTuple,
+	partial bool, fields fieldset) {
+
+	flusher, _ := w.(http.Flusher)
+	started := false
+	for tuple := range resultCh {
+		if tuple.err == nil && !tuple.found && !partial {
+			continue
+		}
+		if tuple.err != nil && !partial && !started {
+			writeThingError(w, tuple.uuid, tuple.err)
+			return
+		}
+		if !started {
+			w.Header().Set("Content-Type", representation.contentType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
 
-infiniteUntilClosed:
-	for {
-		select {
-		case tuple, open := <-uctCh:
-			if !open {
-				break infiniteUntilClosed
-			}
-			things[tuple.uuid] = tuple.concept
-		case err := <-errCh:
-			return nil, err
+		tuple.concept = fields.applyTo(tuple.concept)
+		if err := representation.streamThing(w, tuple); err != nil {
+			logger.Errorf("Error streaming thing with uuid %s, err=%s", tuple.uuid, err.Error())
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if tuple.err != nil && !partial {
+			return
 		}
 	}
-	return things, nil
+	if !started {
+		w.Header().Set("Content-Type", representation.contentType)
+		w.WriteHeader(http.StatusOK)
+	}
 }
 
-func closeOnDone(uctCh chan *uuidConceptTuple, wg *sync.WaitGroup) {
+func closeOnDone(resultCh chan *uuidResultTuple, wg *sync.WaitGroup) {
 	wg.Wait()
-	close(uctCh)
+	close(resultCh)
 }
 
-type uuidConceptTuple struct {
+type uuidResultTuple struct {
 	uuid    string
 	concept Concept
-}
-
-type uuidErrorTuple struct {
-	uuid string
-	err  error
+	found   bool
+	chain   []string
+	err     error
 }
 
 func validateUUID(uuids ...string) error {
@@ -291,91 +660,103 @@ func validateUUID(uuids ...string) error {
 	return nil
 }
 
-func (rh *ThingsHandler) getThingViaConceptsApi(UUID string, relationships []string, transID string) (Concept, bool, error) {
-	mappedConcept := Concept{}
-
-	u, err := url.Parse(rh.conceptsURL)
-	if err != nil {
-		msg := fmt.Sprint("URL of Concepts API is invalid")
-		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
-		return mappedConcept, false, err
-	}
-	u.Path = "/concepts/" + UUID
-	q := u.Query()
-	for _, relationship := range relationships {
-		q.Add("showRelationship", relationship)
-	}
-	u.RawQuery = q.Encode()
-	reqURL := u.String()
-	request, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		msg := fmt.Sprintf("failed to create request to %s", reqURL)
-		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
-		return mappedConcept, false, err
-	}
-
-	request.Header.Set("X-Request-Id", transID)
-
-	resp, err := rh.client.Do(request)
-	if err != nil {
-		msg := fmt.Sprintf("request to %s was unsuccessful", reqURL)
-		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
-		return mappedConcept, false, err
+// getThing serves the concept, or the fact there is none, from the caches when possible and falls back to
+// public-concepts-api otherwise. Expired concepts are served stale, flagged with a warning, while being refreshed or
+// when public-concepts-api fails.
+func (rh *ThingsHandler) getThing(ctx context.Context, uuid string, relationships []string, transID string) (Concept, bool, error) {
+	key := cacheKey(uuid, relationships)
+	if rh.cache != nil {
+		if thing, found := rh.cache.get(key); found {
+			return thing, true, nil
+		}
+		if thing, found := rh.cache.stale(key, rh.staleWhileRevalidate); found {
+			go rh.revalidate(key, uuid, relationships, transID)
+			thing.staleWarning = warningStale
+			return thing, true, nil
+		}
 	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return mappedConcept, false, nil
+	if rh.notFound != nil {
+		if _, found := rh.notFound.get(key); found {
+			return Concept{}, false, nil
+		}
 	}
 
-	conceptsApiResponse := ConceptApiResponse{}
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		msg := fmt.Sprintf("failed to read response body: %v", resp.Body)
-		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
-		return mappedConcept, false, err
-	}
-	if err = json.Unmarshal(body, &conceptsApiResponse); err != nil {
-		msg := fmt.Sprintf("failed to unmarshal response body: %v", body)
-		logger.WithError(err).WithUUID(UUID).WithTransactionID(transID).Error(msg)
-		return mappedConcept, false, err
-	}
-	var altLabels []string
-	mappedConcept.ID = convertID(conceptsApiResponse.ID)
-	mappedConcept.APIURL = mapper.APIURL(UUID, []string{extractFinalSectionOfString(conceptsApiResponse.Type)}, "")
-	mappedConcept.PrefLabel = conceptsApiResponse.PrefLabel
-	mappedConcept.IsDeprecated = conceptsApiResponse.IsDeprecated
-	mappedConcept.DirectType = conceptsApiResponse.Type
-	mappedConcept.Types = mapper.FullTypeHierarchy(conceptsApiResponse.Type)
-
-	for _, keypair := range conceptsApiResponse.AlternativeLabels {
-		switch {
-		case keypair.Type == aliasLabelURI:
-			altLabels = append(altLabels, keypair.Value)
-		case keypair.Type == shortLabelURI:
-			mappedConcept.ShortLabel = keypair.Value
+	thing, found, err := rh.fetchThing(ctx, key, uuid, relationships, transID)
+	if err != nil && rh.cache != nil {
+		if stale, found := rh.cache.stale(key, rh.staleIfError); found {
+			logger.WithError(err).WithUUID(uuid).WithTransactionID(transID).Warn("Serving stale thing as public-concepts-api failed")
+			stale.staleWarning = warningRevalidationFailed
+			return stale, true, nil
 		}
 	}
-	mappedConcept.Aliases = altLabels
-	mappedConcept.DescriptionXML = conceptsApiResponse.DescriptionXML
-	mappedConcept.ImageURL = conceptsApiResponse.ImageURL
-	for _, social := range conceptsApiResponse.Account {
-		mapTypedValues(&mappedConcept, social)
-	}
-	mappedConcept.ScopeNote = conceptsApiResponse.ScopeNote
+	return thing, found, err
+}
 
-	if len(conceptsApiResponse.Broader) > 0 {
-		mappedConcept.BroaderConcepts = convertRelationship(conceptsApiResponse.Broader)
-	}
-	if len(conceptsApiResponse.Narrower) > 0 {
-		mappedConcept.NarrowerConcepts = convertRelationship(conceptsApiResponse.Narrower)
+// vouchedThing returns the copy of the thing retained by the cache if it is current whenever public-concepts-api
+// reports the thing as not modified since the given date, that is if the copy was modified at that date or later.
+func (rh *ThingsHandler) vouchedThing(key string, since time.Time) (Concept, bool) {
+	if rh.cache == nil || since.IsZero() {
+		return Concept{}, false
 	}
-	if len(conceptsApiResponse.Related) > 0 {
-		mappedConcept.RelatedConcepts = convertRelationship(conceptsApiResponse.Related)
+	thing, found := rh.cache.retained(key)
+	if !found || thing.lastModified.IsZero() || thing.lastModified.Truncate(time.Second).Before(since) {
+		return Concept{}, false
 	}
+	return thing, true
+}
 
-	return mappedConcept, true, nil
+// revalidate refreshes a stale cache entry in the background.
+func (rh *ThingsHandler) revalidate(key string, uuid string, relationships []string, transID string) {
+	ctx, cancel := rh.boundedContext(context.Background())
+	defer cancel()
+	rh.fetchThing(ctx, key, uuid, relationships, transID)
+}
+
+// fetchThing reads the concept from the source and caches it. Concurrent lookups for the same uuid and
+// relationships share a single upstream request. When the context carries an If-Modified-Since date and the cache
+// retains a copy of the thing current at that date, the upstream request is conditional, a 304 resulting in the copy
+// so that responses keep their validators and redirects.
+// Things read while the cache is purged are returned but not cached.
+func (rh *ThingsHandler) fetchThing(ctx context.Context, key string, uuid string, relationships []string, transID string) (Concept, bool, error) {
+	// conditional lookups can't share the outcome of unconditional ones
+	flightKey := key
+	since := IfModifiedSince(ctx)
+	vouched, conditional := rh.vouchedThing(key, since)
+	if conditional {
+		flightKey += "#" + since.UTC().Format(http.TimeFormat)
+	} else {
+		since = time.Time{}
+	}
+
+	thing, found, err := rh.flight.do(ctx, flightKey, func(ctx context.Context) (Concept, bool, error) {
+		ctx = transactionidutils.TransactionAwareContext(withIfModifiedSince(ctx, since), transID)
+		var generation, notFoundGeneration uint64
+		if rh.cache != nil {
+			generation = rh.cache.generation()
+		}
+		if rh.notFound != nil {
+			notFoundGeneration = rh.notFound.generation()
+		}
+		thing, found, err := rh.source.Read(ctx, uuid, relationships)
+		if err == ErrNotModified && conditional {
+			thing, found, err = vouched, true, nil
+		}
+		if err == nil && rh.cache != nil {
+			if found {
+				rh.cache.setUnlessPurged(key, thing, generation)
+			} else {
+				rh.cache.remove(key)
+			}
+		}
+		if err == nil && !found && rh.notFound != nil {
+			rh.notFound.setUnlessPurged(key, thing, notFoundGeneration)
+		}
+		return thing, found, err
+	})
+	if err != nil && err == ctx.Err() {
+		err = classifyTransportError(err)
+	}
+	return thing, found, err
 }
 
 func extractFinalSectionOfString(stringToTransform string) string {
@@ -424,30 +805,14 @@ func mapPredicate(conceptPredicate string) string {
 }
 
 func (h *ThingsHandler) Checker() (string, error) {
-	req, err := http.NewRequest("GET", h.conceptsURL+"/__gtg", nil)
-	if err != nil {
-		return "", err
-	}
-
-	resp, err := h.client.Do(req)
-	if err != nil {
-		return "", err
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("health check returned a non-200 HTTP status: %v", resp.StatusCode)
-	}
-	return "Public Concepts API is healthy", nil
-
+	return h.source.Check(context.Background())
 }
 
 func (h *ThingsHandler) GTG() gtg.Status {
 	statusCheck := func() gtg.Status {
 		return gtgCheck(h.Checker)
 	}
-	return gtg.FailFastParallelCheck([]gtg.StatusChecker{statusCheck})()
+	return gtg.FailFastParallelCheck([]gtg.StatusChecker{h.warmUpStatus, statusCheck})()
 }
 
 func gtgCheck(handler func() (string, error)) gtg.Status {
@@ -2,12 +2,14 @@ package things
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,6 +38,7 @@ type mockHTTPClient struct {
 	resp       string
 	statusCode int
 	err        error
+	calls      int
 }
 
 type testCase struct {
@@ -49,6 +52,7 @@ type testCase struct {
 }
 
 func (mhc *mockHTTPClient) Do(req *http.Request) (resp *http.Response, err error) {
+	mhc.calls++
 	cb := ioutil.NopCloser(bytes.NewReader([]byte(mhc.resp)))
 	return &http.Response{Body: cb, StatusCode: mhc.statusCode}, mhc.err
 }
@@ -109,13 +113,13 @@ func TestHandlers(t *testing.T) {
 	}
 
 	getThingWithConceptsAPIInvalidResponse := testCase{
-		"GetThing - Service Unavailable because of concepts api internal server error",
+		"GetThing - Bad Gateway because of concepts api invalid response",
 		"/things/6773e864-78ab-4051-abc2-f4e9ab423ebc",
 		200,
 		`{"foo":bar}`,
 		nil,
-		503,
-		`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebc, err=invalid character 'b' looking for beginning of value"}`,
+		502,
+		`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebc, err=public-concepts-api returned an invalid response: invalid character 'b' looking for beginning of value"}`,
 	}
 
 	getThingRedirect := testCase{
@@ -175,7 +179,8 @@ func TestHandlers(t *testing.T) {
 		getCompleteThingAsConcept,
 		nil,
 		200,
-		`{"things":{"6773e864-78ab-4051-abc2-f4e9ab423ebc":` + transformedCompleteThing + `}}`,
+		`{"things":{"6773e864-78ab-4051-abc2-f4e9ab423ebc":` + transformedCompleteThing + `}` +
+			`,"redirects":{"6773e864-78ab-4051-abc2-f4e9ab423ebc":["6773e864-78ab-4051-abc2-f4e9ab423ebc","6773e864-78ab-4051-abc2-f4e9ab423ebb"]}}`,
 	}
 
 	getBrand := testCase{
@@ -259,6 +264,206 @@ func TestMethodNotAllowed(t *testing.T) {
 	assert.Equal(t, 405, rr.Code, "TestMethodNotAllowed failed: status codes do not match!")
 }
 
+func TestPostThings(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			"PostThings - request with alternative uuid, which returns canonical uuid",
+			`{"uuids":["6773e864-78ab-4051-abc2-f4e9ab423ebc"],"showRelationship":["related"]}`,
+			200,
+			transformBody(`{"things":{"6773e864-78ab-4051-abc2-f4e9ab423ebc":` + transformedCompleteThing + `}` +
+				`,"redirects":{"6773e864-78ab-4051-abc2-f4e9ab423ebc":["6773e864-78ab-4051-abc2-f4e9ab423ebc","6773e864-78ab-4051-abc2-f4e9ab423ebb"]}}`),
+		},
+		{
+			"PostThings - request without uuids",
+			`{"uuids":[]}`,
+			400,
+			`{"message":"at least one uuid should be provided for batch operations"}`,
+		},
+		{
+			"PostThings - request with invalid body",
+			`["6773e864-78ab-4051-abc2-f4e9ab423ebc"]`,
+			400,
+			`{"message":"request body should be a JSON object like {\"uuids\":[...],\"showRelationship\":[...]}"}`,
+		},
+		{
+			"PostThings - request with invalid format UUID",
+			`{"uuids":["6773e864-111178ab-4051-abc2-f4e9ab423ebc"]}`,
+			400,
+			`{"message":"Invalid uuid: 6773e864-111178ab-4051-abc2-f4e9ab423ebc, err: uuid: incorrect UUID length: 6773e864-111178ab-4051-abc2-f4e9ab423ebc"}`,
+		},
+		{
+			"PostThings - request exceeding the maximum batch size",
+			`{"uuids":["6773e864-78ab-4051-abc2-f4e9ab423ebb","6773e864-78ab-4051-abc2-f4e9ab423ebc","6773e864-78ab-4051-abc2-f4e9ab423ebd"]}`,
+			413,
+			`{"message":"at most 2 uuids can be requested in a single batch operation"}`,
+		},
+		{
+			"PostThings - request body too large",
+			`{"uuids":["` + strings.Repeat("a", maxBatchBodyBytes) + `"]}`,
+			413,
+			`{"message":"request body should not be larger than 1048576 bytes"}`,
+		},
+	}
+
+	for _, test := range tests {
+		mockClient := mockHTTPClient{resp: getCompleteThingAsConcept, statusCode: 200}
+		router := mux.NewRouter()
+		handler := NewHandler(&mockClient, "localhost:8080", WithMaxBatchSize(2))
+		handler.RegisterHandlers(router)
+
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/things", strings.NewReader(test.body))
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.expectedCode, rr.Code, test.name+" failed: status codes do not match!")
+		assert.Equal(t, test.expectedBody, rr.Body.String(), test.name+" failed: status body does not match!")
+	}
+}
+
+func TestGetThingsExceedingMaxBatchSize(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	mockClient := mockHTTPClient{resp: getCompleteThingAsConcept, statusCode: 200}
+	router := mux.NewRouter()
+	handler := NewHandler(&mockClient, "localhost:8080", WithMaxBatchSize(1))
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things?uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb&uuid=6773e864-78ab-4051-abc2-f4e9ab423ebc", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Equal(t, 0, mockClient.calls)
+}
+
+// pathHTTPClient answers requests according to their path, defaulting to a 404.
+type pathHTTPClient struct {
+	sync.Mutex
+	responses map[string]mockResponse
+	calls     map[string]int
+}
+
+func (c *pathHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[req.URL.Path]++
+	r, found := c.responses[req.URL.Path]
+	if !found {
+		r = mockResponse{statusCode: http.StatusNotFound}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(r.body))), StatusCode: r.statusCode}, nil
+}
+
+func TestGetThingsPartialResults(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb": {statusCode: 200, body: getCompleteThingAsConcept},
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebd": {err: errors.New("connection refused")},
+	}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080")
+	handler.RegisterHandlers(router)
+
+	url := "/things?uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb&uuid=6773e864-78ab-4051-abc2-f4e9ab423ebc&uuid=6773e864-78ab-4051-abc2-f4e9ab423ebd"
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", url+"&partial=true", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(`{"things":{"6773e864-78ab-4051-abc2-f4e9ab423ebb":`+transformedCompleteThing+`},`+
+		`"errors":{"6773e864-78ab-4051-abc2-f4e9ab423ebd":"connection refused"},`+
+		`"notFound":["6773e864-78ab-4051-abc2-f4e9ab423ebc"]}`), rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", url, nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, `{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebd, err=connection refused"}`, rr.Body.String())
+}
+
+// blockingHTTPClient never answers, returning once the context of the request is done.
+type blockingHTTPClient struct {
+	sync.Mutex
+	calls int
+}
+
+func (c *blockingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.Lock()
+	c.calls++
+	c.Unlock()
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestGetThingRequestTimeout(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &blockingHTTPClient{}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithRequestTimeout(20*time.Millisecond))
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	assert.Equal(t, `{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=context deadline exceeded"}`, rr.Body.String())
+}
+
+func TestGetThingsRequestTimeoutAbandonsRemainingUUIDs(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &blockingHTTPClient{}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithRequestTimeout(20*time.Millisecond), WithBatchConcurrency(1))
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things?partial=true&uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb&uuid=6773e864-78ab-4051-abc2-f4e9ab423ebc", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"things":{},"errors":{"6773e864-78ab-4051-abc2-f4e9ab423ebb":"context deadline exceeded","6773e864-78ab-4051-abc2-f4e9ab423ebc":"context deadline exceeded"}}`+"\n", rr.Body.String())
+	assert.Equal(t, 1, client.calls, "the second uuid should have been abandoned without calling public-concepts-api")
+}
+
+func TestGetThingCancelledByClient(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &blockingHTTPClient{}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(rr, req.WithContext(ctx))
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cancelled request should not wait for public-concepts-api")
+	}
+}
+
 func TestHealthCheck(t *testing.T) {
 	logger.InitLogger("test service", "debug")
 
@@ -674,16 +879,16 @@ func transformBody(testBody string) string {
 	return stripTabs + "\n"
 }
 
-type mockedDriver struct {
+type mockedSource struct {
 	mock.Mock
 }
 
-func (m *mockedDriver) read(id string, relationships []string) (thing Concept, found bool, err error) {
-	args := m.Called(id, relationships)
+func (m *mockedSource) Read(ctx context.Context, uuid string, relationships []string) (thing Concept, found bool, err error) {
+	args := m.Called(ctx, uuid, relationships)
 	return args.Get(0).(Concept), args.Bool(1), args.Error(2)
 }
 
-func (m *mockedDriver) checkConnectivity() error {
-	args := m.Called()
-	return args.Error(0)
+func (m *mockedSource) Check(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
 }
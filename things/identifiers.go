@@ -0,0 +1,40 @@
+package things
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var uuidRegexp = regexp.MustCompile("^" + validUUID)
+
+// thingIDPrefixes are the prefixes of the IDs of things, which end with their uuid.
+var thingIDPrefixes = []string{ftThing, thingsApiUrl}
+
+// uuidFromID extracts the uuid, lowercased, from the ID of a thing as given by public-concepts-api
+// (http://www.ft.com/thing/{uuid}) or by this api (http://api.ft.com/things/{uuid}).
+func uuidFromID(id string) (string, bool) {
+	for _, prefix := range thingIDPrefixes {
+		if len(id) <= len(prefix) || !strings.EqualFold(id[:len(prefix)], prefix) {
+			continue
+		}
+		if uuid := id[len(prefix):]; uuidRegexp.MatchString(uuid) {
+			return strings.ToLower(uuid), true
+		}
+	}
+	return "", false
+}
+
+// sameUUID tells whether both uuids identify the same thing, uuids being case insensitive.
+func sameUUID(uuid string, other string) bool {
+	return strings.EqualFold(uuid, other)
+}
+
+// thingURL rebuilds the URL of a request for a thing as the URL of the thing with the given uuid, keeping the query.
+func thingURL(requested *url.URL, uuid string) string {
+	u := *requested
+	u.Path = path.Join(path.Dir(u.Path), uuid)
+	u.RawPath = ""
+	return u.String()
+}
@@ -0,0 +1,62 @@
+package things
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDFromID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		uuid string
+		ok   bool
+	}{
+		{"public-concepts-api id", "http://www.ft.com/thing/6773e864-78ab-4051-abc2-f4e9ab423ebb", "6773e864-78ab-4051-abc2-f4e9ab423ebb", true},
+		{"public-things-api id", "http://api.ft.com/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", "6773e864-78ab-4051-abc2-f4e9ab423ebb", true},
+		{"upper case", "HTTP://API.FT.COM/things/6773E864-78AB-4051-ABC2-F4E9AB423EBB", "6773e864-78ab-4051-abc2-f4e9ab423ebb", true},
+		{"unknown prefix", "http://www.ft.com/people/6773e864-78ab-4051-abc2-f4e9ab423ebb", "", false},
+		{"trailing path", "http://api.ft.com/things/6773e864-78ab-4051-abc2-f4e9ab423ebb/other", "", false},
+		{"nested uuid", "http://api.ft.com/things/other/6773e864-78ab-4051-abc2-f4e9ab423ebb", "", false},
+		{"prefix only", "http://api.ft.com/things/", "", false},
+		{"empty", "", "", false},
+	}
+	for _, test := range tests {
+		uuid, ok := uuidFromID(test.id)
+		assert.Equal(t, test.uuid, uuid, test.name)
+		assert.Equal(t, test.ok, ok, test.name)
+	}
+}
+
+func TestThingURLKeepsTheQuery(t *testing.T) {
+	requested, _ := url.Parse("/things/6773e864-78ab-4051-abc2-f4e9ab423eba?showRelationship=related&q=6773e864-78ab-4051-abc2-f4e9ab423eba")
+
+	assert.Equal(t, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb?showRelationship=related&q=6773e864-78ab-4051-abc2-f4e9ab423eba",
+		thingURL(requested, "6773e864-78ab-4051-abc2-f4e9ab423ebb"))
+}
+
+func TestGetThingComparesUUIDsCaseInsensitively(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, client := chainedHandler(false)
+	client.responses["/concepts/"+strings.ToUpper(chainUUIDC)] = conceptIdentifiedBy(chainUUIDC)
+
+	rr := serveThing(router, "/things/"+strings.ToUpper(chainUUIDC), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"http://api.ft.com/things/`+chainUUIDC+`"`)
+}
+
+func TestGetThingRedirectsFromThePath(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(false)
+
+	rr := serveThing(router, "/things/"+chainUUIDA+"?from="+chainUUIDA, nil)
+
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/things/"+chainUUIDB+"?from="+chainUUIDA, rr.Header().Get("Location"))
+}
@@ -0,0 +1,153 @@
+package things
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	skosNamespace = "http://www.w3.org/2004/02/skos/core#"
+	ftNamespace   = "http://www.ft.com/ontology/"
+)
+
+// jsonLDContext maps the fields of things to RDF terms, relationships being keyed by their predicate.
+var jsonLDContext = map[string]interface{}{
+	"skos":             skosNamespace,
+	"ft":               ftNamespace,
+	"prefLabel":        "skos:prefLabel",
+	"aliases":          "skos:altLabel",
+	"scopeNote":        "skos:scopeNote",
+	"shortLabel":       "ft:shortLabel",
+	"descriptionXML":   "ft:descriptionXML",
+	"emailAddress":     "ft:emailAddress",
+	"facebookPage":     "ft:facebookPage",
+	"twitterHandle":    "ft:twitterHandle",
+	"isDeprecated":     "ft:isDeprecated",
+	"apiUrl":           map[string]string{"@id": "ft:apiUrl", "@type": "@id"},
+	"_imageUrl":        map[string]string{"@id": "ft:imageUrl", "@type": "@id"},
+	"directType":       map[string]string{"@id": "ft:directType", "@type": "@id"},
+	"broaderConcepts":  "skos:broader",
+	"narrowerConcepts": "skos:narrower",
+	"relatedConcepts":  "skos:related",
+}
+
+// jsonLDRelationshipTerms are the terms of the relationships whose predicate has one in the context.
+var jsonLDRelationshipTerms = map[string]string{
+	broaderPredicate:  "broaderConcepts",
+	narrowerPredicate: "narrowerConcepts",
+	relatedPredicate:  "relatedConcepts",
+}
+
+type jsonLDNode map[string]interface{}
+
+func encodeJSONLDThing(w io.Writer, thing Concept) error {
+	node, err := jsonLDThing(thing)
+	if err != nil {
+		return err
+	}
+	node["@context"] = jsonLDContext
+	return json.NewEncoder(w).Encode(node)
+}
+
+// encodeJSONLDThings returns the things of a batch as a @graph, ordered by requested uuid. Things requested with
+// several uuids are only listed once.
+func encodeJSONLDThings(w io.Writer, result *ThingsResponse) error {
+	uuids := make([]string, 0, len(result.Things))
+	for uuid := range result.Things {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+
+	graph := []jsonLDNode{}
+	listed := map[string]bool{}
+	for _, uuid := range uuids {
+		thing := result.Things[uuid]
+		if listed[thing.ID] {
+			continue
+		}
+		listed[thing.ID] = true
+		node, err := jsonLDThing(thing)
+		if err != nil {
+			return err
+		}
+		graph = append(graph, node)
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		Context   map[string]interface{} `json:"@context"`
+		Graph     []jsonLDNode           `json:"@graph"`
+		Redirects map[string][]string    `json:"redirects,omitempty"`
+		Errors    map[string]string      `json:"errors,omitempty"`
+		NotFound  []string               `json:"notFound,omitempty"`
+	}{jsonLDContext, graph, result.Redirects, result.Errors, result.NotFound})
+}
+
+// jsonLDThing returns the thing as a node identified by its ID, with its relationships grouped by predicate.
+func jsonLDThing(thing Concept) (jsonLDNode, error) {
+	node, err := newJSONLDNode(thing)
+	if err != nil {
+		return nil, err
+	}
+
+	relationships := []struct {
+		term    string
+		related []Thing
+	}{
+		{"broaderConcepts", thing.BroaderConcepts},
+		{"narrowerConcepts", thing.NarrowerConcepts},
+		{"relatedConcepts", thing.RelatedConcepts},
+	}
+	for _, relationship := range relationships {
+		delete(node, relationship.term)
+		for _, related := range relationship.related {
+			relatedNode, err := newJSONLDNode(related)
+			if err != nil {
+				return nil, err
+			}
+			delete(relatedNode, "predicate")
+
+			term := relationship.term
+			if related.Predicate != "" {
+				term = jsonLDPredicateTerm(related.Predicate)
+			}
+			nodes, _ := node[term].([]jsonLDNode)
+			node[term] = append(nodes, relatedNode)
+		}
+	}
+	return node, nil
+}
+
+// jsonLDPredicateTerm returns the term of the predicate in the context, or its compact IRI.
+func jsonLDPredicateTerm(predicate string) string {
+	if term, found := jsonLDRelationshipTerms[predicate]; found {
+		return term
+	}
+	if strings.HasPrefix(predicate, skosNamespace) {
+		return "skos:" + strings.TrimPrefix(predicate, skosNamespace)
+	}
+	if strings.HasPrefix(predicate, ftNamespace) {
+		return "ft:" + strings.TrimPrefix(predicate, ftNamespace)
+	}
+	return predicate
+}
+
+// newJSONLDNode returns the JSON fields of the thing, its id and types becoming the @id and @type keywords.
+func newJSONLDNode(thing interface{}) (jsonLDNode, error) {
+	encoded, err := json.Marshal(thing)
+	if err != nil {
+		return nil, err
+	}
+	node := jsonLDNode{}
+	if err := json.Unmarshal(encoded, &node); err != nil {
+		return nil, err
+	}
+	node["@id"] = node["id"]
+	if types, found := node["types"]; found && types != nil {
+		node["@type"] = types
+	}
+	delete(node, "id")
+	delete(node, "types")
+	return node, nil
+}
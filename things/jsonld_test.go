@@ -0,0 +1,73 @@
+package things
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var acceptJSONLD = http.Header{"Accept": []string{"application/ld+json"}}
+
+func TestGetThingAsJSONLD(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source, err := NewDirectorySource("fixtures")
+	assert.NoError(t, err)
+	router := sourceHandler(source)
+
+	rr := serveThing(router, "/things/"+onyxPikeUUID+"?showRelationship=broaderTransitive&showRelationship=related", acceptJSONLD)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/ld+json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept, X-Resolve-Canonical", rr.Header().Get("Vary"))
+
+	var node map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &node))
+	context := node["@context"].(map[string]interface{})
+	assert.Equal(t, "skos:prefLabel", context["prefLabel"])
+	assert.Equal(t, "skos:altLabel", context["aliases"])
+	assert.Equal(t, "skos:related", context["relatedConcepts"])
+	assert.Equal(t, "http://api.ft.com/things/"+onyxPikeUUID, node["@id"])
+	assert.Equal(t, []interface{}{"http://www.ft.com/ontology/core/Thing", "http://www.ft.com/ontology/concept/Concept",
+		"http://www.ft.com/ontology/Topic"}, node["@type"])
+	assert.Equal(t, "Onyx Pike", node["prefLabel"])
+	assert.NotContains(t, node, "id")
+	assert.NotContains(t, node, "broaderConcepts", "broader transitive concepts should be keyed by their own predicate")
+
+	broaderTransitive := node["skos:broaderTransitive"].([]interface{})
+	assert.Len(t, broaderTransitive, 2)
+	related := node["relatedConcepts"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "http://api.ft.com/things/"+onyxPikeRelatedUUID, related["@id"])
+	assert.Equal(t, "Onyx Pike Related", related["prefLabel"])
+	assert.NotContains(t, related, "predicate")
+}
+
+func TestGetThingsAsJSONLD(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source, err := NewDirectorySource("fixtures")
+	assert.NoError(t, err)
+	router := sourceHandler(source)
+
+	rr := serveThing(router, "/things?partial=true&uuid="+onyxPikeRelatedUUID+"&uuid="+onyxPikeUUID+
+		"&uuid=4c4738cb-45df-43fe-ac7c-bab963b698ea&uuid="+canonicalUUID, acceptJSONLD)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/ld+json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+
+	var document struct {
+		Context   map[string]interface{}   `json:"@context"`
+		Graph     []map[string]interface{} `json:"@graph"`
+		Redirects map[string][]string      `json:"redirects"`
+		NotFound  []string                 `json:"notFound"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+	assert.Equal(t, "skos:prefLabel", document.Context["prefLabel"])
+	assert.Len(t, document.Graph, 2, "things requested with several uuids should be listed once")
+	assert.Equal(t, "http://api.ft.com/things/"+onyxPikeUUID, document.Graph[0]["@id"])
+	assert.Equal(t, "http://api.ft.com/things/"+onyxPikeRelatedUUID, document.Graph[1]["@id"])
+	assert.Contains(t, document.Redirects, "4c4738cb-45df-43fe-ac7c-bab963b698ea")
+	assert.Equal(t, []string{canonicalUUID}, document.NotFound)
+}
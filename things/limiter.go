@@ -0,0 +1,70 @@
+package things
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// ErrUpstreamSaturated is returned when a request to public-concepts-api waited too long for an in-flight slot.
+var ErrUpstreamSaturated = errors.New("too many in-flight requests to public-concepts-api")
+
+// limitingClient wraps an HttpClient and caps the number of requests in flight across all callers.
+// Requests queue for a free slot for up to queueTimeout. A slot is held until the response body is closed.
+type limitingClient struct {
+	client       HttpClient
+	slots        chan struct{}
+	queueTimeout time.Duration
+	saturated    metrics.Counter
+}
+
+func newLimitingClient(client HttpClient, maxInFlight int, queueTimeout time.Duration) *limitingClient {
+	return &limitingClient{
+		client:       client,
+		slots:        make(chan struct{}, maxInFlight),
+		queueTimeout: queueTimeout,
+		saturated:    metrics.GetOrRegisterCounter("things.upstream.saturated", metrics.DefaultRegistry),
+	}
+}
+
+func (c *limitingClient) Do(req *http.Request) (*http.Response, error) {
+	timer := time.NewTimer(c.queueTimeout)
+	select {
+	case c.slots <- struct{}{}:
+		timer.Stop()
+	case <-timer.C:
+		c.saturated.Inc(1)
+		return nil, ErrUpstreamSaturated
+	case <-req.Context().Done():
+		timer.Stop()
+		return nil, req.Context().Err()
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() { <-c.slots })
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil || resp == nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releasingBody{resp.Body, release}
+	return resp, err
+}
+
+// releasingBody frees the in-flight slot of a request once its response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,105 @@
+package things
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrencyHTTPClient answers every request with a 404 after a short delay, tracking the maximum number of
+// requests it served concurrently.
+type concurrencyHTTPClient struct {
+	sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (c *concurrencyHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.Lock()
+	c.active++
+	if c.active > c.maxActive {
+		c.maxActive = c.active
+	}
+	c.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	c.Lock()
+	c.active--
+	c.Unlock()
+	return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(nil)), StatusCode: http.StatusNotFound}, nil
+}
+
+func TestLimitingClientRejectsRequestsWhenSaturated(t *testing.T) {
+	mockClient := mockHTTPClient{statusCode: http.StatusOK}
+	limiter := newLimitingClient(&mockClient, 1, 10*time.Millisecond)
+	req, _ := http.NewRequest("GET", "/concepts/"+canonicalUUID, nil)
+
+	resp, err := limiter.Do(req)
+	assert.NoError(t, err)
+
+	_, err = limiter.Do(req)
+	assert.Equal(t, ErrUpstreamSaturated, err, "the slot should be held until the response body is closed")
+
+	resp.Body.Close()
+	resp, err = limiter.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, mockClient.calls)
+}
+
+func TestGetThingsRespectsBatchConcurrency(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &concurrencyHTTPClient{}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithBatchConcurrency(2))
+	handler.RegisterHandlers(router)
+
+	url := "/things?partial=true"
+	for i := 0; i < 8; i++ {
+		url += fmt.Sprintf("&uuid=6773e864-78ab-4051-abc2-f4e9ab423eb%d", i)
+	}
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", url, nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "6773e864-78ab-4051-abc2-f4e9ab423eb7")
+	assert.True(t, client.maxActive <= 2, "at most 2 things should have been fetched concurrently, got %d", client.maxActive)
+}
+
+func TestGetThingsRespectsUpstreamConcurrency(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &concurrencyHTTPClient{}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithUpstreamConcurrency(3, time.Second))
+	handler.RegisterHandlers(router)
+
+	var wg sync.WaitGroup
+	for r := 0; r < 3; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			url := "/things?partial=true"
+			for i := 0; i < 5; i++ {
+				url += fmt.Sprintf("&uuid=6773e864-78ab-4051-abc2-f4e9ab423e%d%d", r, i)
+			}
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", url, nil)
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+		}(r)
+	}
+	wg.Wait()
+
+	assert.True(t, client.maxActive <= 3, "at most 3 requests should have been in flight, got %d", client.maxActive)
+}
@@ -1,5 +1,7 @@
 package things
 
+import "time"
+
 type Things []Concept
 
 type Concept struct {
@@ -20,6 +22,13 @@ type Concept struct {
 	BroaderConcepts  []Thing  `json:"broaderConcepts,omitempty"`
 	RelatedConcepts  []Thing  `json:"relatedConcepts,omitempty"`
 	IsDeprecated     bool     `json:"isDeprecated,omitempty"`
+
+	// lastModified is the modification date reported by public-concepts-api, if any
+	lastModified time.Time
+	// staleWarning is set when the concept is served stale from the cache
+	staleWarning string
+	// fields are the fields requested with the fields query parameter, if any
+	fields fieldset
 }
 
 type Thing struct {
@@ -30,6 +39,27 @@ type Thing struct {
 	DirectType   string   `json:"directType,omitempty"`
 	Predicate    string   `json:"predicate,omitempty"`
 	IsDeprecated bool     `json:"isDeprecated,omitempty"`
+
+	// fields are the fields of related things requested with the fields query parameter, if any
+	fields fieldset
+}
+
+// ThingsRequest is the body of a POST /things batch request.
+type ThingsRequest struct {
+	UUIDs         []string `json:"uuids"`
+	Relationships []string `json:"showRelationship,omitempty"`
+}
+
+// ThingsResponse is the body returned by batch operations. Errors and NotFound are only filled in
+// when partial results are requested. Redirects lists the uuids traversed to resolve alternate uuids.
+type ThingsResponse struct {
+	Things    map[string]Concept  `json:"things"`
+	Redirects map[string][]string `json:"redirects,omitempty"`
+	Errors    map[string]string   `json:"errors,omitempty"`
+	NotFound  []string            `json:"notFound,omitempty"`
+
+	// requested are the uuids of the batch, in the order they were first requested
+	requested []string
 }
 
 type ConceptApiResponse struct {
@@ -52,8 +82,8 @@ type TypedValue struct {
 }
 
 type Relationship struct {
-	Concept   BasicConcept `json:concept,omitempty`
-	Predicate string       `json:predicate,omitempty`
+	Concept   BasicConcept `json:"concept,omitempty"`
+	Predicate string       `json:"predicate,omitempty"`
 }
 
 type BasicConcept struct {
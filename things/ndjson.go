@@ -0,0 +1,24 @@
+package things
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ndjsonFailure is the line reporting a requested uuid without a thing.
+type ndjsonFailure struct {
+	UUID     string `json:"uuid"`
+	Error    string `json:"error,omitempty"`
+	NotFound bool   `json:"notFound,omitempty"`
+}
+
+// streamNDJSONThing writes the thing of a requested uuid as a line of JSON, or the reason there is none.
+func streamNDJSONThing(w io.Writer, result *uuidResultTuple) error {
+	switch {
+	case result.err != nil:
+		return json.NewEncoder(w).Encode(ndjsonFailure{UUID: result.uuid, Error: result.err.Error()})
+	case !result.found:
+		return json.NewEncoder(w).Encode(ndjsonFailure{UUID: result.uuid, NotFound: true})
+	}
+	return json.NewEncoder(w).Encode(result.concept)
+}
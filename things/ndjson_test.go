@@ -0,0 +1,49 @@
+package things
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetThingsAsNDJSON(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source := rdfTestSource()
+	source.On("Read", mock.Anything, thirdCanonicalUUID, []string(nil)).Return(Concept{}, false, errors.New("boom"))
+	router := sourceHandler(source, WithBatchConcurrency(1))
+
+	rr := serveThing(router, "/things?partial=true&fields=prefLabel&uuid="+canonicalUUID+"&uuid="+secondCanonicalUUID+"&uuid="+thirdCanonicalUUID,
+		http.Header{"Accept": []string{"application/x-ndjson"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, []string{
+		`{"id":"http://api.ft.com/things/` + canonicalUUID + `","prefLabel":"The \"Onyx\" Pike"}`,
+		`{"uuid":"` + secondCanonicalUUID + `","notFound":true}`,
+		`{"uuid":"` + thirdCanonicalUUID + `","error":"boom"}`,
+	}, strings.Split(strings.TrimSuffix(rr.Body.String(), "\n"), "\n"))
+}
+
+func TestGetThingsAsNDJSONFailsBeforeStreaming(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source := rdfTestSource()
+	source.On("Read", mock.Anything, thirdCanonicalUUID, []string(nil)).Return(Concept{}, false, errors.New("boom"))
+	router := sourceHandler(source, WithBatchConcurrency(1))
+
+	rr := serveThing(router, "/things?format=ndjson&uuid="+thirdCanonicalUUID+"&uuid="+canonicalUUID, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, `{"message":"Error getting thing with uuid `+thirdCanonicalUUID+`, err=boom"}`, rr.Body.String())
+
+	rr = serveThing(router, "/things?format=ndjson&uuid="+canonicalUUID+"&uuid="+secondCanonicalUUID+"&uuid="+thirdCanonicalUUID, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, strings.HasPrefix(rr.Body.String(), `{"id":"http://api.ft.com/things/`+canonicalUUID+`",`))
+	assert.True(t, strings.HasSuffix(rr.Body.String(), "\n"+`{"uuid":"`+thirdCanonicalUUID+`","error":"boom"}`+"\n"),
+		"the error should end the stream once things were written")
+	assert.NotContains(t, rr.Body.String(), secondCanonicalUUID, "uuids not found should be left out")
+}
@@ -0,0 +1,88 @@
+package things
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/transactionid-utils-go"
+	"github.com/gorilla/mux"
+)
+
+// WithPurgeToken enables the admin endpoints purging the cached things, authenticated with the given bearer token.
+// An empty token leaves them disabled.
+func WithPurgeToken(token string) Option {
+	return func(h *ThingsHandler) {
+		h.purgeToken = token
+	}
+}
+
+func (h *ThingsHandler) registerPurgeHandlers(router *mux.Router) {
+	if h.purgeToken == "" {
+		return
+	}
+	router.HandleFunc("/__cache/things/{uuid}", h.PurgeThing).Methods("DELETE")
+	router.HandleFunc("/__cache/things", h.PurgeThings).Methods("DELETE")
+}
+
+// PurgeThing handler evicts the cached thing for every combination of requested relationships, along with the cached
+// things redirecting to it and the fact it was not found.
+func (rh *ThingsHandler) PurgeThing(w http.ResponseWriter, r *http.Request) {
+	uuid := strings.ToLower(mux.Vars(r)["uuid"])
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	if !rh.authorizedToPurge(w, r) {
+		return
+	}
+	if err := validateUUID(uuid); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf(`{"message":"%v"}`, err)))
+		return
+	}
+
+	purged := rh.purge(func(key string, concept Concept) bool {
+		conceptUUID, _ := uuidFromID(concept.ID)
+		return strings.HasPrefix(strings.ToLower(key), uuid+"?") || conceptUUID == uuid
+	})
+	logger.WithTransactionID(transactionidutils.GetTransactionIDFromRequest(r)).WithUUID(uuid).
+		Infof("Purged %d cached things", purged)
+	w.Write([]byte(fmt.Sprintf(`{"purged":%d}`, purged)))
+}
+
+// PurgeThings handler evicts every cached thing.
+func (rh *ThingsHandler) PurgeThings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	if !rh.authorizedToPurge(w, r) {
+		return
+	}
+
+	purged := rh.purge(func(string, Concept) bool { return true })
+	logger.WithTransactionID(transactionidutils.GetTransactionIDFromRequest(r)).Infof("Purged all %d cached things", purged)
+	w.Write([]byte(fmt.Sprintf(`{"purged":%d}`, purged)))
+}
+
+func (rh *ThingsHandler) authorizedToPurge(w http.ResponseWriter, r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if strings.HasPrefix(auth, "Bearer ") &&
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(rh.purgeToken)) == 1 {
+		return true
+	}
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"message":"a valid bearer token is required to purge the cache"}`))
+	return false
+}
+
+func (rh *ThingsHandler) purge(matches func(key string, concept Concept) bool) int {
+	purged := 0
+	if rh.cache != nil {
+		purged += rh.cache.purge(matches)
+	}
+	if rh.notFound != nil {
+		purged += rh.notFound.purge(matches)
+	}
+	return purged
+}
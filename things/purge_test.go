@@ -0,0 +1,135 @@
+package things
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPurgeToken = "s3cr3t"
+
+func purgeCache(router *mux.Router, url string, token string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", url, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestPurgeThing(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb": {statusCode: 200, body: getCompleteThingAsConcept},
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebc": {statusCode: 200, body: getCompleteThingAsConcept},
+		"/concepts/c3e3fe44-93fb-11e8-8f42-da24cd01f044": {statusCode: 200, body: brandAsConcept},
+	}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080", WithConceptCache(10, time.Minute), WithPurgeToken(testPurgeToken))
+	handler.RegisterHandlers(router)
+
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb?showRelationship=related", nil)
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebc", nil)
+	serveThing(router, "/things/c3e3fe44-93fb-11e8-8f42-da24cd01f044", nil)
+	assert.Equal(t, 4, handler.cache.lru.Len())
+
+	rr := purgeCache(router, "/__cache/things/6773E864-78AB-4051-ABC2-F4E9AB423EBB", testPurgeToken)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"purged":3}`, rr.Body.String(), "both relationship variants and the redirect to the thing should be purged")
+	assert.Equal(t, 1, handler.cache.lru.Len())
+
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	assert.Equal(t, 3, client.calls["/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"], "purged thing should be fetched again")
+}
+
+func TestPurgeThings(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb": {statusCode: 200, body: getCompleteThingAsConcept},
+	}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080", WithConceptCache(10, time.Minute),
+		WithNegativeCache(10, time.Minute), WithPurgeToken(testPurgeToken))
+	handler.RegisterHandlers(router)
+
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebd", nil)
+
+	rr := purgeCache(router, "/__cache/things", testPurgeToken)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"purged":2}`, rr.Body.String())
+	assert.Equal(t, 0, handler.cache.lru.Len())
+	assert.Equal(t, 0, handler.notFound.lru.Len())
+}
+
+func TestPurgeRequiresToken(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := mux.NewRouter()
+	handler := NewHandler(&pathHTTPClient{}, "http://localhost:8080", WithConceptCache(10, time.Minute), WithPurgeToken(testPurgeToken))
+	handler.RegisterHandlers(router)
+
+	for _, token := range []string{"", "wrong"} {
+		rr := purgeCache(router, "/__cache/things", token)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, `{"message":"a valid bearer token is required to purge the cache"}`, rr.Body.String())
+	}
+
+	rr := purgeCache(router, "/__cache/things/not-a-uuid", testPurgeToken)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestPurgeDisabledWithoutToken(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := mux.NewRouter()
+	handler := NewHandler(&pathHTTPClient{}, "http://localhost:8080", WithConceptCache(10, time.Minute))
+	handler.RegisterHandlers(router)
+
+	rr := purgeCache(router, "/__cache/things", "")
+	assert.NotEqual(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, http.StatusUnauthorized, rr.Code)
+}
+
+// gatedHTTPClient answers once released, telling when a request is waiting.
+type gatedHTTPClient struct {
+	response mockResponse
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func (c *gatedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return &http.Response{Body: ioutil.NopCloser(strings.NewReader(c.response.body)), StatusCode: c.response.statusCode}, nil
+}
+
+func TestPurgeWhileFetching(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	for _, response := range []mockResponse{{statusCode: 200, body: getCompleteThingAsConcept}, {statusCode: http.StatusNotFound}} {
+		client := &gatedHTTPClient{response: response, started: make(chan struct{}), release: make(chan struct{})}
+		router := mux.NewRouter()
+		handler := NewHandler(client, "http://localhost:8080", WithConceptCache(10, time.Minute),
+			WithNegativeCache(10, time.Minute), WithPurgeToken(testPurgeToken))
+		handler.RegisterHandlers(router)
+
+		done := make(chan int)
+		go func() {
+			done <- serveThing(router, "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil).Code
+		}()
+		<-client.started
+		assert.Equal(t, http.StatusOK, purgeCache(router, "/__cache/things", testPurgeToken).Code)
+		close(client.release)
+
+		assert.Equal(t, response.statusCode, <-done)
+		assert.Equal(t, 0, handler.cache.lru.Len(), "a thing read before the purge should not be cached")
+		assert.Equal(t, 0, handler.notFound.lru.Len(), "a thing missing before the purge should not be cached")
+	}
+}
@@ -0,0 +1,272 @@
+package things
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	rdfNamespace    = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	skosxlNamespace = "http://www.w3.org/2008/05/skos-xl#"
+)
+
+// rdfPrefixes are the namespaces Turtle output abbreviates, in the order they are declared.
+var rdfPrefixes = []struct{ prefix, namespace string }{
+	{"rdf", rdfNamespace},
+	{"skos", skosNamespace},
+	{"skosxl", skosxlNamespace},
+	{"ft", ftNamespace},
+}
+
+var rdfLocalNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// rdfObject is either a resource, identified by an IRI or a blank node label starting with _:, or a plain literal.
+type rdfObject struct {
+	value   string
+	literal bool
+}
+
+type rdfTriple struct {
+	subject   string
+	predicate string
+	object    rdfObject
+}
+
+// rdfGraph is a set of triples describing things, kept in the order they were added and grouped by subject.
+type rdfGraph struct {
+	subjects   []string
+	triples    map[string][]rdfTriple
+	seen       map[rdfTriple]bool
+	blankNodes int
+}
+
+func newRDFGraph() *rdfGraph {
+	return &rdfGraph{triples: map[string][]rdfTriple{}, seen: map[rdfTriple]bool{}}
+}
+
+func (g *rdfGraph) add(subject string, predicate string, object rdfObject) {
+	t := rdfTriple{subject: subject, predicate: predicate, object: object}
+	if g.seen[t] {
+		return
+	}
+	g.seen[t] = true
+	if _, found := g.triples[subject]; !found {
+		g.subjects = append(g.subjects, subject)
+	}
+	g.triples[subject] = append(g.triples[subject], t)
+}
+
+// newBlankNode returns the label of a resource without an IRI, unique within the graph.
+func (g *rdfGraph) newBlankNode() string {
+	g.blankNodes++
+	return fmt.Sprintf("_:b%d", g.blankNodes)
+}
+
+func (g *rdfGraph) addLiteral(subject string, predicate string, value string) {
+	if value != "" {
+		g.add(subject, predicate, rdfObject{value: value, literal: true})
+	}
+}
+
+// addThing describes the thing, as mapped for the JSON representation, as a SKOS concept with its types, labels, scope
+// note and social accounts, and links it to the things it has relationships with, which are described with their
+// types and label. The short label is a SKOS-XL label, as SKOS has no property for it.
+func (g *rdfGraph) addThing(thing Concept) {
+	g.addTypes(thing.ID, thing.Types)
+	g.addLiteral(thing.ID, skosNamespace+"prefLabel", thing.PrefLabel)
+	for _, alias := range thing.Aliases {
+		g.addLiteral(thing.ID, skosNamespace+"altLabel", alias)
+	}
+	if thing.ShortLabel != "" {
+		label := g.newBlankNode()
+		g.add(thing.ID, skosxlNamespace+"altLabel", rdfObject{value: label})
+		g.add(label, rdfNamespace+"type", rdfObject{value: skosxlNamespace + "Label"})
+		g.addLiteral(label, skosxlNamespace+"literalForm", thing.ShortLabel)
+	}
+	g.addLiteral(thing.ID, skosNamespace+"scopeNote", thing.ScopeNote)
+	g.addLiteral(thing.ID, emailAddressURI, thing.EmailAddress)
+	g.addLiteral(thing.ID, facebookPageURI, thing.FacebookPage)
+	g.addLiteral(thing.ID, twitterURI, thing.TwitterHandle)
+
+	relationships := []struct {
+		predicate string
+		related   []Thing
+	}{
+		{broaderPredicate, thing.BroaderConcepts},
+		{narrowerPredicate, thing.NarrowerConcepts},
+		{relatedPredicate, thing.RelatedConcepts},
+	}
+	for _, relationship := range relationships {
+		for _, related := range relationship.related {
+			predicate := related.Predicate
+			if predicate == "" {
+				predicate = relationship.predicate
+			}
+			g.add(thing.ID, predicate, rdfObject{value: related.ID})
+			g.addTypes(related.ID, related.Types)
+			g.addLiteral(related.ID, skosNamespace+"prefLabel", related.PrefLabel)
+		}
+	}
+}
+
+// addTypes types the thing as a SKOS concept, followed by its own types.
+func (g *rdfGraph) addTypes(id string, types []string) {
+	g.add(id, rdfNamespace+"type", rdfObject{value: skosNamespace + "Concept"})
+	for _, thingType := range types {
+		g.add(id, rdfNamespace+"type", rdfObject{value: thingType})
+	}
+}
+
+// addThings describes the things of a batch, ordered by requested uuid.
+func (g *rdfGraph) addThings(result *ThingsResponse) {
+	uuids := make([]string, 0, len(result.Things))
+	for uuid := range result.Things {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		g.addThing(result.Things[uuid])
+	}
+}
+
+func (g *rdfGraph) writeNTriples(w *bufio.Writer) {
+	for _, subject := range g.subjects {
+		for _, t := range g.triples[subject] {
+			fmt.Fprintf(w, "%s <%s> %s .\n", formatRDFResource(t.subject), escapeIRI(t.predicate), formatRDFObject(t.object))
+		}
+	}
+}
+
+func (g *rdfGraph) writeTurtle(w *bufio.Writer) {
+	for _, p := range rdfPrefixes {
+		fmt.Fprintf(w, "@prefix %s: <%s> .\n", p.prefix, p.namespace)
+	}
+	for _, subject := range g.subjects {
+		fmt.Fprintf(w, "\n%s", formatRDFResource(subject))
+		triples := g.triples[subject]
+		for i, t := range triples {
+			if i == 0 || t.predicate != triples[i-1].predicate {
+				if i > 0 {
+					w.WriteString(" ;")
+				}
+				fmt.Fprintf(w, "\n    %s %s", turtlePredicate(t.predicate), formatRDFObject(t.object))
+				continue
+			}
+			fmt.Fprintf(w, ", %s", formatRDFObject(t.object))
+		}
+		w.WriteString(" .\n")
+	}
+}
+
+// writeRDFComments reports the uuids of a batch which could not be described, as comments both N-Triples and
+// Turtle allow.
+func writeRDFComments(w *bufio.Writer, result *ThingsResponse) {
+	for _, comment := range rdfComments(result) {
+		fmt.Fprintf(w, "# %s\n", comment)
+	}
+}
+
+// rdfComments returns single line comments listing the uuids of a batch which were not found or failed.
+func rdfComments(result *ThingsResponse) []string {
+	var comments []string
+	for _, uuid := range result.NotFound {
+		comments = append(comments, "not found: "+uuid)
+	}
+	uuids := make([]string, 0, len(result.Errors))
+	for uuid := range result.Errors {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		comments = append(comments, "error: "+uuid+" "+strings.Replace(result.Errors[uuid], "\n", " ", -1))
+	}
+	return comments
+}
+
+func encodeNTriplesThing(w io.Writer, thing Concept) error {
+	g := newRDFGraph()
+	g.addThing(thing)
+	buffered := bufio.NewWriter(w)
+	g.writeNTriples(buffered)
+	return buffered.Flush()
+}
+
+func encodeNTriplesThings(w io.Writer, result *ThingsResponse) error {
+	g := newRDFGraph()
+	g.addThings(result)
+	buffered := bufio.NewWriter(w)
+	writeRDFComments(buffered, result)
+	g.writeNTriples(buffered)
+	return buffered.Flush()
+}
+
+func encodeTurtleThing(w io.Writer, thing Concept) error {
+	g := newRDFGraph()
+	g.addThing(thing)
+	buffered := bufio.NewWriter(w)
+	g.writeTurtle(buffered)
+	return buffered.Flush()
+}
+
+func encodeTurtleThings(w io.Writer, result *ThingsResponse) error {
+	g := newRDFGraph()
+	g.addThings(result)
+	buffered := bufio.NewWriter(w)
+	writeRDFComments(buffered, result)
+	g.writeTurtle(buffered)
+	return buffered.Flush()
+}
+
+func turtlePredicate(predicate string) string {
+	if predicate == rdfNamespace+"type" {
+		return "a"
+	}
+	for _, p := range rdfPrefixes {
+		if local := strings.TrimPrefix(predicate, p.namespace); local != predicate && rdfLocalNameRegexp.MatchString(local) {
+			return p.prefix + ":" + local
+		}
+	}
+	return "<" + escapeIRI(predicate) + ">"
+}
+
+// formatRDFObject formats the object the same way in N-Triples and Turtle.
+func formatRDFObject(object rdfObject) string {
+	if object.literal {
+		return `"` + escapeLiteral(object.value) + `"`
+	}
+	return formatRDFResource(object.value)
+}
+
+func formatRDFResource(resource string) string {
+	if isBlankNode(resource) {
+		return resource
+	}
+	return "<" + escapeIRI(resource) + ">"
+}
+
+func isBlankNode(resource string) bool {
+	return strings.HasPrefix(resource, "_:")
+}
+
+var literalEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func escapeLiteral(value string) string {
+	return literalEscaper.Replace(value)
+}
+
+// escapeIRI escapes the characters N-Triples and Turtle do not allow in IRIs.
+func escapeIRI(iri string) string {
+	var escaped strings.Builder
+	for _, r := range iri {
+		if r <= 0x20 || strings.ContainsRune(`<>"{}|^`+"`"+`\`, r) {
+			fmt.Fprintf(&escaped, `\u%04X`, r)
+			continue
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
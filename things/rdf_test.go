@@ -0,0 +1,113 @@
+package things
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var rdfTestThing = Concept{
+	ID:         "http://api.ft.com/things/" + canonicalUUID,
+	APIURL:     "http://api.ft.com/things/" + canonicalUUID,
+	PrefLabel:  `The "Onyx" Pike`,
+	Types:      []string{"http://www.ft.com/ontology/core/Thing", "http://www.ft.com/ontology/Topic"},
+	DirectType: "http://www.ft.com/ontology/Topic",
+	Aliases:    []string{"Bob", "BOB2"},
+	ShortLabel: "Onyx",
+	ScopeNote:  "line one\nline two",
+	BroaderConcepts: []Thing{{
+		ID:        "http://api.ft.com/things/" + alternateUUID,
+		PrefLabel: "Onyx Pike Broader",
+		Types:     []string{"http://www.ft.com/ontology/Topic"},
+		Predicate: broaderTransitivePredicate,
+	}},
+	RelatedConcepts: []Thing{{
+		ID:        "http://api.ft.com/things/" + alternateUUID,
+		PrefLabel: "Onyx Pike Broader",
+		Types:     []string{"http://www.ft.com/ontology/Topic"},
+		Predicate: "http://www.ft.com/ontology/supersedes",
+	}},
+}
+
+func rdfTestSource() *mockedSource {
+	source := new(mockedSource)
+	source.On("Read", mock.Anything, canonicalUUID, []string(nil)).Return(rdfTestThing, true, nil)
+	source.On("Read", mock.Anything, secondCanonicalUUID, []string(nil)).Return(Concept{}, false, nil)
+	return source
+}
+
+func TestGetThingAsNTriples(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things/"+canonicalUUID, http.Header{"Accept": []string{"application/n-triples"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/n-triples", rr.Header().Get("Content-Type"))
+	thing := "<http://api.ft.com/things/" + canonicalUUID + ">"
+	broader := "<http://api.ft.com/things/" + alternateUUID + ">"
+	assert.Equal(t, thing+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.w3.org/2004/02/skos/core#Concept> .\n"+
+		thing+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.ft.com/ontology/core/Thing> .\n"+
+		thing+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.ft.com/ontology/Topic> .\n"+
+		thing+` <http://www.w3.org/2004/02/skos/core#prefLabel> "The \"Onyx\" Pike" .`+"\n"+
+		thing+` <http://www.w3.org/2004/02/skos/core#altLabel> "Bob" .`+"\n"+
+		thing+` <http://www.w3.org/2004/02/skos/core#altLabel> "BOB2" .`+"\n"+
+		thing+" <http://www.w3.org/2008/05/skos-xl#altLabel> _:b1 .\n"+
+		thing+` <http://www.w3.org/2004/02/skos/core#scopeNote> "line one\nline two" .`+"\n"+
+		thing+" <http://www.w3.org/2004/02/skos/core#broaderTransitive> "+broader+" .\n"+
+		thing+" <http://www.ft.com/ontology/supersedes> "+broader+" .\n"+
+		"_:b1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.w3.org/2008/05/skos-xl#Label> .\n"+
+		`_:b1 <http://www.w3.org/2008/05/skos-xl#literalForm> "Onyx" .`+"\n"+
+		broader+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.w3.org/2004/02/skos/core#Concept> .\n"+
+		broader+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://www.ft.com/ontology/Topic> .\n"+
+		broader+` <http://www.w3.org/2004/02/skos/core#prefLabel> "Onyx Pike Broader" .`+"\n",
+		rr.Body.String())
+}
+
+func TestGetThingAsTurtle(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things/"+canonicalUUID, http.Header{"Accept": []string{"text/turtle"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/turtle; charset=UTF-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix skos: <http://www.w3.org/2004/02/skos/core#> .
+@prefix skosxl: <http://www.w3.org/2008/05/skos-xl#> .
+@prefix ft: <http://www.ft.com/ontology/> .
+
+<http://api.ft.com/things/`+canonicalUUID+`>
+    a <http://www.w3.org/2004/02/skos/core#Concept>, <http://www.ft.com/ontology/core/Thing>, <http://www.ft.com/ontology/Topic> ;
+    skos:prefLabel "The \"Onyx\" Pike" ;
+    skos:altLabel "Bob", "BOB2" ;
+    skosxl:altLabel _:b1 ;
+    skos:scopeNote "line one\nline two" ;
+    skos:broaderTransitive <http://api.ft.com/things/`+alternateUUID+`> ;
+    ft:supersedes <http://api.ft.com/things/`+alternateUUID+`> .
+
+_:b1
+    a <http://www.w3.org/2008/05/skos-xl#Label> ;
+    skosxl:literalForm "Onyx" .
+
+<http://api.ft.com/things/`+alternateUUID+`>
+    a <http://www.w3.org/2004/02/skos/core#Concept>, <http://www.ft.com/ontology/Topic> ;
+    skos:prefLabel "Onyx Pike Broader" .
+`, rr.Body.String())
+}
+
+func TestGetThingsAsTurtle(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things?partial=true&uuid="+canonicalUUID+"&uuid="+secondCanonicalUUID,
+		http.Header{"Accept": []string{"text/turtle"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/turtle; charset=UTF-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "# not found: "+secondCanonicalUUID+"\n@prefix rdf:")
+	assert.Contains(t, rr.Body.String(), "\n<http://api.ft.com/things/"+canonicalUUID+">\n    a ")
+}
@@ -0,0 +1,92 @@
+package things
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlQName returns the qualified name of the predicate, along with the namespace to declare on its element when it is
+// not one of the rdfPrefixes.
+func xmlQName(predicate string) (name string, namespace string, err error) {
+	for _, p := range rdfPrefixes {
+		if local := strings.TrimPrefix(predicate, p.namespace); local != predicate && rdfLocalNameRegexp.MatchString(local) {
+			return p.prefix + ":" + local, "", nil
+		}
+	}
+	split := strings.LastIndexAny(predicate, "#/") + 1
+	if split == 0 || !rdfLocalNameRegexp.MatchString(predicate[split:]) {
+		return "", "", fmt.Errorf("predicate %s cannot be written as RDF/XML", predicate)
+	}
+	return "ns:" + predicate[split:], predicate[:split], nil
+}
+
+func xmlEscape(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
+
+// writeRDFXML writes each subject as an rdf:Description, blank nodes being identified by their rdf:nodeID.
+func (g *rdfGraph) writeRDFXML(w *bufio.Writer, comments []string) error {
+	w.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n<rdf:RDF")
+	for _, p := range rdfPrefixes {
+		fmt.Fprintf(w, ` xmlns:%s="%s"`, p.prefix, xmlEscape(p.namespace))
+	}
+	w.WriteString(">\n")
+	for _, comment := range comments {
+		fmt.Fprintf(w, "  <!-- %s -->\n", xmlEscape(strings.Replace(comment, "--", "- -", -1)))
+	}
+
+	for _, subject := range g.subjects {
+		fmt.Fprintf(w, "  <rdf:Description %s>\n", rdfXMLResourceAttribute("rdf:about", subject))
+		for _, t := range g.triples[subject] {
+			name, namespace, err := xmlQName(t.predicate)
+			if err != nil {
+				return err
+			}
+			declaration := ""
+			if namespace != "" {
+				declaration = fmt.Sprintf(` xmlns:ns="%s"`, xmlEscape(namespace))
+			}
+			if t.object.literal {
+				fmt.Fprintf(w, "    <%s%s>%s</%s>\n", name, declaration, xmlEscape(t.object.value), name)
+				continue
+			}
+			fmt.Fprintf(w, "    <%s%s %s/>\n", name, declaration, rdfXMLResourceAttribute("rdf:resource", t.object.value))
+		}
+		w.WriteString("  </rdf:Description>\n")
+	}
+	w.WriteString("</rdf:RDF>\n")
+	return nil
+}
+
+// rdfXMLResourceAttribute refers to the resource with the attribute, or with rdf:nodeID when it is a blank node.
+func rdfXMLResourceAttribute(attribute string, resource string) string {
+	if isBlankNode(resource) {
+		return fmt.Sprintf(`rdf:nodeID="%s"`, strings.TrimPrefix(resource, "_:"))
+	}
+	return fmt.Sprintf(`%s="%s"`, attribute, xmlEscape(resource))
+}
+
+func encodeRDFXMLThing(w io.Writer, thing Concept) error {
+	g := newRDFGraph()
+	g.addThing(thing)
+	buffered := bufio.NewWriter(w)
+	if err := g.writeRDFXML(buffered, nil); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+func encodeRDFXMLThings(w io.Writer, result *ThingsResponse) error {
+	g := newRDFGraph()
+	g.addThings(result)
+	buffered := bufio.NewWriter(w)
+	if err := g.writeRDFXML(buffered, rdfComments(result)); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
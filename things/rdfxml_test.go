@@ -0,0 +1,79 @@
+package things
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetThingAsRDFXML(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things/"+canonicalUUID, http.Header{"Accept": []string{"application/rdf+xml"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/rdf+xml; charset=UTF-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:skos="http://www.w3.org/2004/02/skos/core#" xmlns:skosxl="http://www.w3.org/2008/05/skos-xl#" xmlns:ft="http://www.ft.com/ontology/">
+  <rdf:Description rdf:about="http://api.ft.com/things/`+canonicalUUID+`">
+    <rdf:type rdf:resource="http://www.w3.org/2004/02/skos/core#Concept"/>
+    <rdf:type rdf:resource="http://www.ft.com/ontology/core/Thing"/>
+    <rdf:type rdf:resource="http://www.ft.com/ontology/Topic"/>
+    <skos:prefLabel>The &#34;Onyx&#34; Pike</skos:prefLabel>
+    <skos:altLabel>Bob</skos:altLabel>
+    <skos:altLabel>BOB2</skos:altLabel>
+    <skosxl:altLabel rdf:nodeID="b1"/>
+    <skos:scopeNote>line one&#xA;line two</skos:scopeNote>
+    <skos:broaderTransitive rdf:resource="http://api.ft.com/things/`+alternateUUID+`"/>
+    <ft:supersedes rdf:resource="http://api.ft.com/things/`+alternateUUID+`"/>
+  </rdf:Description>
+  <rdf:Description rdf:nodeID="b1">
+    <rdf:type rdf:resource="http://www.w3.org/2008/05/skos-xl#Label"/>
+    <skosxl:literalForm>Onyx</skosxl:literalForm>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://api.ft.com/things/`+alternateUUID+`">
+    <rdf:type rdf:resource="http://www.w3.org/2004/02/skos/core#Concept"/>
+    <rdf:type rdf:resource="http://www.ft.com/ontology/Topic"/>
+    <skos:prefLabel>Onyx Pike Broader</skos:prefLabel>
+  </rdf:Description>
+</rdf:RDF>
+`, rr.Body.String())
+}
+
+func TestGetThingsAsRDFXML(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := sourceHandler(rdfTestSource())
+
+	rr := serveThing(router, "/things?partial=true&uuid="+canonicalUUID+"&uuid="+secondCanonicalUUID,
+		http.Header{"Accept": []string{"application/rdf+xml"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "  <!-- not found: "+secondCanonicalUUID+" -->\n")
+	decoder := xml.NewDecoder(strings.NewReader(rr.Body.String()))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			assert.Equal(t, "EOF", err.Error(), "the response should be well formed XML")
+			break
+		}
+	}
+}
+
+func TestXMLQName(t *testing.T) {
+	name, namespace, err := xmlQName("http://www.w3.org/2004/02/skos/core#exactMatch")
+	assert.NoError(t, err)
+	assert.Equal(t, "skos:exactMatch", name)
+	assert.Empty(t, namespace)
+
+	name, namespace, err = xmlQName("http://purl.org/dc/terms/subject")
+	assert.NoError(t, err)
+	assert.Equal(t, "ns:subject", name)
+	assert.Equal(t, "http://purl.org/dc/terms/", namespace)
+
+	_, _, err = xmlQName("http://example.com/")
+	assert.EqualError(t, err, "predicate http://example.com/ cannot be written as RDF/XML")
+}
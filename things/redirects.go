@@ -0,0 +1,136 @@
+package things
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// resolveCanonicalHeader asks GetThing for the canonical thing instead of a redirect to it, as does the
+// resolveCanonical=true query parameter.
+const resolveCanonicalHeader = "X-Resolve-Canonical"
+
+// WithMaxRedirects sets how many times a thing redirecting to another one, through its alternate uuids, is followed.
+// GetThing follows up to maxRedirects redirects before redirecting the client, while batch operations follow one
+// more to reach the canonical thing. Values below 1 are treated as 1.
+func WithMaxRedirects(maxRedirects int) Option {
+	return func(h *ThingsHandler) {
+		h.maxRedirects = maxRedirects
+	}
+}
+
+// resolveMode tells resolveThing what is needed of the things the requested one redirects to.
+type resolveMode int
+
+const (
+	// resolveStrict reads every thing of the chain like the requested one, failing when any of them cannot be read.
+	resolveStrict resolveMode = iota
+	// resolveRedirect only looks up where the things of the chain redirect to, reading them without relationships.
+	// The chain ends with any of them which cannot be read, for the client to be redirected to it nonetheless.
+	resolveRedirect
+	// resolveCanonical reads every thing of the chain like the requested one, the chain ending with any of them which
+	// cannot be read.
+	resolveCanonical
+)
+
+// resolution is the outcome of following the redirects from a requested uuid towards its canonical thing.
+type resolution struct {
+	// thing is the requested thing when the chain is only looked up, the last thing read otherwise
+	thing Concept
+	found bool
+	// chain lists the requested uuid followed by the uuids it redirects to
+	chain []string
+	// resolved tells whether the chain ends with the canonical thing
+	resolved bool
+}
+
+func (r resolution) redirected() bool {
+	return len(r.chain) > 1
+}
+
+func (r resolution) target() string {
+	return r.chain[len(r.chain)-1]
+}
+
+func formatRedirectChain(chain []string) string {
+	return strings.Join(chain, " -> ")
+}
+
+// resolveThing gets the thing for the uuid and follows the uuids it redirects to, fetching at most maxFetches
+// things as the mode asks. Redirects looping back to a uuid already traversed fail with a 508.
+func (rh *ThingsHandler) resolveThing(ctx context.Context, uuid string, relationships []string, transID string,
+	maxFetches int, mode resolveMode) (resolution, error) {
+
+	res := resolution{chain: []string{uuid}}
+	for fetches := 1; ; fetches++ {
+		current := res.target()
+		var thing Concept
+		var found bool
+		var err error
+		if fetches > 1 && mode == resolveRedirect {
+			thing, found, err = rh.getThing(withIfModifiedSince(ctx, time.Time{}), current, nil, transID)
+		} else {
+			thing, found, err = rh.getThing(ctx, current, relationships, transID)
+		}
+		if fetches > 1 && mode != resolveStrict && (err != nil || !found) {
+			return res, nil
+		}
+		if err != nil || !found {
+			res.found = false
+			return res, err
+		}
+		if fetches == 1 || mode != resolveRedirect {
+			res.thing = thing
+		}
+		res.found = true
+
+		// concepts without a uuid in their ID are rejected when decoded
+		next, ok := uuidFromID(thing.ID)
+		if !ok || sameUUID(next, current) {
+			res.resolved = true
+			return res, nil
+		}
+
+		for _, traversed := range res.chain {
+			if sameUUID(traversed, next) {
+				res.chain = append(res.chain, next)
+				return res, &UpstreamError{
+					Status: http.StatusLoopDetected,
+					err:    fmt.Errorf("alternate uuids redirect in a cycle: %s", formatRedirectChain(res.chain)),
+				}
+			}
+		}
+		res.chain = append(res.chain, next)
+
+		if fetches >= maxFetches {
+			return res, nil
+		}
+	}
+}
+
+func resolveCanonicalRequested(r *http.Request) bool {
+	return r.URL.Query().Get("resolveCanonical") == "true" || strings.EqualFold(r.Header.Get(resolveCanonicalHeader), "true")
+}
+
+// setCanonicalHeaders tells the client which thing is returned in place of the requested one.
+func setCanonicalHeaders(w http.ResponseWriter, r *http.Request, uuid string, res resolution) {
+	w.Header().Set("X-Requested-UUID", uuid)
+	w.Header().Set("X-Canonical-UUID", res.target())
+	location := *r.URL
+	query := location.Query()
+	query.Del("resolveCanonical")
+	location.RawQuery = query.Encode()
+	w.Header().Set("Content-Location", thingURL(&location, res.target()))
+	if res.redirected() {
+		w.Header().Set("X-Redirect-Chain", formatRedirectChain(res.chain))
+	}
+}
+
+func (rh *ThingsHandler) redirectsFollowed() int {
+	if rh.maxRedirects < 1 {
+		return 1
+	}
+	return rh.maxRedirects
+}
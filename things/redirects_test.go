@@ -0,0 +1,161 @@
+package things
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chainUUIDA = "6773e864-78ab-4051-abc2-f4e9ab423eba"
+	chainUUIDB = "6773e864-78ab-4051-abc2-f4e9ab423ebb"
+	chainUUIDC = "6773e864-78ab-4051-abc2-f4e9ab423ebc"
+)
+
+func conceptIdentifiedBy(uuid string) mockResponse {
+	return mockResponse{statusCode: 200, body: fmt.Sprintf(
+		`{"id":"http://api.ft.com/things/%s","type":"http://www.ft.com/ontology/Topic","prefLabel":"Chained"}`, uuid)}
+}
+
+// chainedHandler serves a->b->c, c being the canonical thing, or a->b->a when cyclic.
+func chainedHandler(cyclic bool, options ...Option) (*mux.Router, *pathHTTPClient) {
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/concepts/" + chainUUIDA: conceptIdentifiedBy(chainUUIDB),
+		"/concepts/" + chainUUIDB: conceptIdentifiedBy(chainUUIDC),
+		"/concepts/" + chainUUIDC: conceptIdentifiedBy(chainUUIDC),
+	}}
+	if cyclic {
+		client.responses["/concepts/"+chainUUIDB] = conceptIdentifiedBy(chainUUIDA)
+	}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "http://localhost:8080", options...)
+	handler.RegisterHandlers(router)
+	return router, client
+}
+
+func TestGetThingFollowsRedirectChain(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(false, WithMaxRedirects(3))
+
+	rr := serveThing(router, "/things/"+chainUUIDA+"?showRelationship=related", nil)
+
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/things/"+chainUUIDC+"?showRelationship=related", rr.Header().Get("Location"))
+	assert.Equal(t, chainUUIDA+" -> "+chainUUIDB+" -> "+chainUUIDC, rr.Header().Get("X-Redirect-Chain"))
+}
+
+func TestGetThingRedirectsOneHopByDefault(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, client := chainedHandler(false)
+
+	rr := serveThing(router, "/things/"+chainUUIDA, nil)
+
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/things/"+chainUUIDB, rr.Header().Get("Location"))
+	assert.Equal(t, chainUUIDA+" -> "+chainUUIDB, rr.Header().Get("X-Redirect-Chain"))
+	assert.Equal(t, 0, client.calls["/concepts/"+chainUUIDB], "the redirect target should be left to the client")
+}
+
+func TestGetThingDetectsRedirectCycles(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(true, WithMaxRedirects(5))
+
+	rr := serveThing(router, "/things/"+chainUUIDA, nil)
+
+	assert.Equal(t, http.StatusLoopDetected, rr.Code)
+	assert.Equal(t, fmt.Sprintf(`{"message":"Error getting thing with uuid %s, err=alternate uuids redirect in a cycle: %s -> %s -> %s"}`,
+		chainUUIDA, chainUUIDA, chainUUIDB, chainUUIDA), rr.Body.String())
+}
+
+func TestGetThingsFollowsRedirectChain(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(false, WithMaxRedirects(2))
+
+	rr := serveThing(router, "/things?uuid="+chainUUIDA, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, fmt.Sprintf(`{"things":{"%s":{"id":"http://api.ft.com/things/%s","apiUrl":"http://api.ft.com/things/%s",`+
+		`"prefLabel":"Chained","types":["http://www.ft.com/ontology/core/Thing","http://www.ft.com/ontology/concept/Concept","http://www.ft.com/ontology/Topic"],`+
+		`"directType":"http://www.ft.com/ontology/Topic"}},"redirects":{"%s":["%s","%s","%s"]}}`+"\n",
+		chainUUIDA, chainUUIDC, chainUUIDC, chainUUIDA, chainUUIDA, chainUUIDB, chainUUIDC), rr.Body.String())
+}
+
+func TestGetThingsGivesUpOnTooLongRedirectChains(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(false)
+
+	rr := serveThing(router, "/things?partial=true&uuid="+chainUUIDA, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"things":{},"notFound":["`+chainUUIDA+`"]}`+"\n", rr.Body.String())
+}
+
+func TestGetThingsReportsRedirectCycles(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(true, WithMaxRedirects(5))
+
+	rr := serveThing(router, "/things?partial=true&uuid="+chainUUIDA+"&uuid="+chainUUIDC, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var result ThingsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+	assert.Equal(t, map[string]string{chainUUIDA: fmt.Sprintf("alternate uuids redirect in a cycle: %s -> %s -> %s",
+		chainUUIDA, chainUUIDB, chainUUIDA)}, result.Errors)
+	assert.Contains(t, result.Things, chainUUIDC)
+}
+
+func TestGetThingResolvesCanonicalThingOnRequest(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, _ := chainedHandler(false, WithMaxRedirects(2))
+
+	rr := serveThing(router, "/things/"+chainUUIDA+"?resolveCanonical=true&showRelationship=related", nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"id":"http://api.ft.com/things/`+chainUUIDC+`"`)
+	assert.Equal(t, chainUUIDA, rr.Header().Get("X-Requested-UUID"))
+	assert.Equal(t, chainUUIDC, rr.Header().Get("X-Canonical-UUID"))
+	assert.Equal(t, "/things/"+chainUUIDC+"?showRelationship=related", rr.Header().Get("Content-Location"))
+	assert.Equal(t, chainUUIDA+" -> "+chainUUIDB+" -> "+chainUUIDC, rr.Header().Get("X-Redirect-Chain"))
+	assert.Equal(t, "Accept, "+resolveCanonicalHeader, rr.Header().Get("Vary"))
+
+	rr = serveThing(router, "/things/"+chainUUIDC, http.Header{resolveCanonicalHeader: []string{"true"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, chainUUIDC, rr.Header().Get("X-Requested-UUID"))
+	assert.Equal(t, chainUUIDC, rr.Header().Get("X-Canonical-UUID"))
+	assert.Empty(t, rr.Header().Get("X-Redirect-Chain"))
+}
+
+func TestGetThingRedirectsWhenCanonicalThingIsOutOfReach(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router, client := chainedHandler(false)
+	client.responses["/concepts/"+chainUUIDC] = conceptIdentifiedBy(chainUUIDA)
+
+	rr := serveThing(router, "/things/"+chainUUIDB, http.Header{resolveCanonicalHeader: []string{"true"}})
+
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/things/"+chainUUIDA, rr.Header().Get("Location"))
+	assert.Empty(t, rr.Header().Get("X-Canonical-UUID"))
+}
+
+func TestGetThingRedirectsToMissingOrFailingCanonicalThing(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	for _, response := range []mockResponse{{statusCode: http.StatusNotFound}, {statusCode: http.StatusServiceUnavailable}} {
+		router, client := chainedHandler(false, WithMaxRedirects(3))
+		client.responses["/concepts/"+chainUUIDC] = response
+
+		rr := serveThing(router, "/things/"+chainUUIDA, nil)
+		assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+		assert.Equal(t, "/things/"+chainUUIDC, rr.Header().Get("Location"))
+		assert.Equal(t, chainUUIDA+" -> "+chainUUIDB+" -> "+chainUUIDC, rr.Header().Get("X-Redirect-Chain"))
+
+		rr = serveThing(router, "/things/"+chainUUIDA+"?resolveCanonical=true", nil)
+		assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+		assert.Equal(t, "/things/"+chainUUIDC+"?resolveCanonical=true", rr.Header().Get("Location"))
+	}
+}
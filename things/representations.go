@@ -0,0 +1,162 @@
+package things
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// representation is a format things can be returned in, chosen according to the Accept header of the request or,
+// for batches, its format query parameter. Representations only available for batches have no encodeThing.
+type representation struct {
+	mediaType    string
+	contentType  string
+	format       string
+	encodeThing  func(w io.Writer, thing Concept) error
+	encodeThings func(w io.Writer, result *ThingsResponse) error
+	// streamThing writes the result of a requested uuid as soon as it is known, for representations streamed
+	// instead of encoded once the batch is complete
+	streamThing func(w io.Writer, result *uuidResultTuple) error
+}
+
+var jsonRepresentation = representation{
+	mediaType:   "application/json",
+	contentType: "application/json; charset=UTF-8",
+	format:      "json",
+	encodeThing: func(w io.Writer, thing Concept) error {
+		return json.NewEncoder(w).Encode(thing)
+	},
+	encodeThings: func(w io.Writer, result *ThingsResponse) error {
+		return json.NewEncoder(w).Encode(result)
+	},
+}
+
+// representations lists the formats on offer, the first one being returned when the request accepts none of them.
+var representations = []representation{
+	jsonRepresentation,
+	{
+		mediaType:    "application/ld+json",
+		format:       "jsonld",
+		contentType:  "application/ld+json",
+		encodeThing:  encodeJSONLDThing,
+		encodeThings: encodeJSONLDThings,
+	},
+	{
+		mediaType:    "text/turtle",
+		format:       "turtle",
+		contentType:  "text/turtle; charset=UTF-8",
+		encodeThing:  encodeTurtleThing,
+		encodeThings: encodeTurtleThings,
+	},
+	{
+		mediaType:    "application/n-triples",
+		format:       "ntriples",
+		contentType:  "application/n-triples",
+		encodeThing:  encodeNTriplesThing,
+		encodeThings: encodeNTriplesThings,
+	},
+	{
+		mediaType:    "application/rdf+xml",
+		format:       "rdfxml",
+		contentType:  "application/rdf+xml; charset=UTF-8",
+		encodeThing:  encodeRDFXMLThing,
+		encodeThings: encodeRDFXMLThings,
+	},
+	{
+		mediaType:    "text/csv",
+		contentType:  "text/csv; charset=UTF-8",
+		format:       "csv",
+		encodeThings: encodeCSVThings,
+	},
+	{
+		mediaType:   "application/x-ndjson",
+		contentType: "application/x-ndjson",
+		format:      "ndjson",
+		streamThing: streamNDJSONThing,
+	},
+}
+
+// thingRepresentations are the representations of single things, batches being available in all of them.
+var thingRepresentations = filterThingRepresentations(representations)
+
+func filterThingRepresentations(offers []representation) []representation {
+	var things []representation
+	for _, offer := range offers {
+		if offer.encodeThing != nil {
+			things = append(things, offer)
+		}
+	}
+	return things
+}
+
+// negotiateRepresentation returns the offered representation the request accepts with the highest quality, the first
+// one listed winning ties.
+func negotiateRepresentation(r *http.Request, offers []representation) representation {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return offers[0]
+	}
+
+	best, bestQuality := offers[0], 0.0
+	for _, offer := range offers {
+		if quality := acceptedQuality(accept, offer.mediaType); quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
+// batchRepresentation returns the representation named by the format query parameter of the request, if any, and
+// negotiates it otherwise.
+func batchRepresentation(r *http.Request) (representation, error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		return negotiateRepresentation(r, representations), nil
+	}
+	for _, offer := range representations {
+		if offer.format == format {
+			return offer, nil
+		}
+	}
+	return representation{}, fmt.Errorf("unknown format %s", format)
+}
+
+// acceptedQuality returns the quality the Accept header gives to the media type, according to the most specific
+// media range matching it.
+func acceptedQuality(accept string, mediaType string) float64 {
+	quality, specificity := 0.0, -1
+	for _, mediaRange := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		var rangeSpecificity int
+		switch {
+		case rangeType == mediaType:
+			rangeSpecificity = 2
+		case strings.HasSuffix(rangeType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(rangeType, "*")):
+			rangeSpecificity = 1
+		case rangeType == "*/*":
+			rangeSpecificity = 0
+		default:
+			continue
+		}
+		if rangeSpecificity < specificity {
+			continue
+		}
+
+		q := 1.0
+		if value, found := params["q"]; found {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		quality, specificity = q, rangeSpecificity
+	}
+	return quality
+}
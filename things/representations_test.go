@@ -0,0 +1,53 @@
+package things
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateRepresentation(t *testing.T) {
+	tests := []struct {
+		accept    string
+		mediaType string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/ld+json", "application/ld+json"},
+		{"application/json;q=0.5, application/ld+json", "application/ld+json"},
+		{"application/ld+json;q=0.5, application/*", "application/json"},
+		{"application/*;q=0.2, application/ld+json;q=0.9", "application/ld+json"},
+		{"text/html", "application/json"},
+		{"text/turtle", "text/turtle"},
+		{"text/*, application/n-triples;q=0.8", "text/turtle"},
+		{"application/n-triples, application/json;q=0.9", "application/n-triples"},
+		{"application/ld+json;q=0, */*", "application/json"},
+		{"application/ld+json;q=oops", "application/json"},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest("GET", "/things", nil)
+		req.Header.Set("Accept", test.accept)
+		assert.Equal(t, test.mediaType, negotiateRepresentation(req, thingRepresentations).mediaType, test.accept)
+	}
+}
+
+func TestBatchRepresentation(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/things?format=ndjson", nil)
+	req.Header.Set("Accept", "text/csv")
+	representation, err := batchRepresentation(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/x-ndjson", representation.mediaType, "the format parameter should win over the Accept header")
+
+	req, _ = http.NewRequest("GET", "/things", nil)
+	req.Header.Set("Accept", "text/csv")
+	representation, err = batchRepresentation(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv", representation.mediaType)
+	assert.Equal(t, "application/json", negotiateRepresentation(req, thingRepresentations).mediaType,
+		"single things should not be offered as CSV")
+
+	req, _ = http.NewRequest("GET", "/things?format=xlsx", nil)
+	_, err = batchRepresentation(req)
+	assert.EqualError(t, err, "unknown format xlsx")
+}
@@ -0,0 +1,92 @@
+package things
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/rcrowley/go-metrics"
+)
+
+// RetryPolicy describes how failed GET requests to public-concepts-api are retried.
+// Transport errors and responses with one of the RetryableStatusCodes are retried up to MaxAttempts
+// attempts in total, waiting between half and all of BaseDelay*2^(attempt-1), capped at MaxDelay, in between.
+type RetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	RetryableStatusCodes []int
+}
+
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return err != ErrCircuitOpen && err != ErrUpstreamSaturated
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry using exponential backoff with equal jitter.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay << uint(retry-1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	half := ceiling / 2
+	return half + time.Duration(rand.Int63n(int64(ceiling-half)+1))
+}
+
+var upstreamRetries = metrics.GetOrRegisterCounter("things.upstream.retries", metrics.DefaultRegistry)
+
+// doWithRetries executes the idempotent request according to the retry policy, giving up early when the next
+// attempt could not complete before the deadline of the request context.
+func (api *ConceptsAPI) doWithRetries(request *http.Request, uuid string, transID string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := api.client.Do(request)
+		if attempt >= api.retries.MaxAttempts || !api.retries.shouldRetry(resp, err) {
+			if attempt > 1 {
+				msg := fmt.Sprintf("request to %s completed after %d attempts", request.URL, attempt)
+				logger.WithUUID(uuid).WithTransactionID(transID).Info(msg)
+			}
+			return resp, err
+		}
+
+		delay := api.retries.backoff(attempt)
+		if deadline, ok := request.Context().Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			msg := fmt.Sprintf("not retrying request to %s after %d attempts, deadline exceeded", request.URL, attempt)
+			logger.WithUUID(uuid).WithTransactionID(transID).Warn(msg)
+			return resp, err
+		}
+
+		msg := fmt.Sprintf("attempt %d of request to %s was unsuccessful, retrying in %v", attempt, request.URL, delay)
+		if err != nil {
+			logger.WithError(err).WithUUID(uuid).WithTransactionID(transID).Warn(msg)
+		} else {
+			logger.WithUUID(uuid).WithTransactionID(transID).Warn(fmt.Sprintf("%s, status=%d", msg, resp.StatusCode))
+		}
+		if resp != nil && resp.Body != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		upstreamRetries.Inc(1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package things
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockResponse struct {
+	statusCode int
+	header     http.Header
+	body       string
+	err        error
+}
+
+// sequenceHTTPClient answers requests with the given responses in order, repeating the last one.
+type sequenceHTTPClient struct {
+	sync.Mutex
+	responses []mockResponse
+	calls     int
+}
+
+func (c *sequenceHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.Lock()
+	defer c.Unlock()
+	r := c.responses[len(c.responses)-1]
+	if c.calls < len(c.responses) {
+		r = c.responses[c.calls]
+	}
+	c.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &http.Response{Body: ioutil.NopCloser(bytes.NewReader([]byte(r.body))), StatusCode: r.statusCode, Header: r.header}, nil
+}
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:          3,
+	BaseDelay:            time.Millisecond,
+	MaxDelay:             5 * time.Millisecond,
+	RetryableStatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+}
+
+func TestRetryPolicyBackoffIsCapped(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for retry := 1; retry < 100; retry++ {
+		delay := policy.backoff(retry)
+		assert.True(t, delay >= 0 && delay <= 50*time.Millisecond, "delay %v out of bounds", delay)
+		if retry > 3 {
+			assert.True(t, delay >= 25*time.Millisecond, "delay %v should be at least half of the maximum", delay)
+		}
+	}
+}
+
+func TestGetThingRetriesRetryableFailures(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &sequenceHTTPClient{responses: []mockResponse{
+		{err: errors.New("connection reset by peer")},
+		{statusCode: http.StatusBadGateway},
+		{statusCode: http.StatusOK, body: getCompleteThingAsConcept},
+	}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithRetries(testRetryPolicy))
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+	assert.Equal(t, 3, client.calls)
+}
+
+func TestGetThingDoesNotRetryNonRetryableStatus(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &sequenceHTTPClient{responses: []mockResponse{{statusCode: http.StatusNotFound}}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080", WithRetries(testRetryPolicy))
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestRetriesStopAtMaxAttempts(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &sequenceHTTPClient{responses: []mockResponse{{err: errors.New("connection refused")}}}
+	handler := NewHandler(client, "localhost:8080", WithRetries(testRetryPolicy))
+
+	req, _ := http.NewRequest("GET", "localhost:8080/concepts/"+canonicalUUID, nil)
+	_, err := handler.conceptsAPI.doWithRetries(req, canonicalUUID, "tid_test")
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 3, client.calls)
+}
+
+func TestRetriesRespectDeadline(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &sequenceHTTPClient{responses: []mockResponse{{statusCode: http.StatusServiceUnavailable}}}
+	policy := testRetryPolicy
+	policy.BaseDelay = time.Hour
+	policy.MaxDelay = time.Hour
+	handler := NewHandler(client, "localhost:8080", WithRetries(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequest("GET", "localhost:8080/concepts/"+canonicalUUID, nil)
+	resp, err := handler.conceptsAPI.doWithRetries(req.WithContext(ctx), canonicalUUID, "tid_test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 1, client.calls)
+}
@@ -0,0 +1,14 @@
+package things
+
+import "context"
+
+// ConceptSource is where the handler reads concepts from, public-concepts-api being the default one.
+type ConceptSource interface {
+	// Read returns the concept with the given uuid and the requested relationships, found being false when there is
+	// no such concept. Errors are reported with the status of an UpstreamError, built with NewUpstreamError, or a 503
+	// otherwise. Sources may answer with ErrNotModified when the concept was not modified since the IfModifiedSince
+	// date of the context. The transaction id of the request is available from the context.
+	Read(ctx context.Context, uuid string, relationships []string) (thing Concept, found bool, err error)
+	// Check describes the health of the source, failing when it cannot serve concepts.
+	Check(ctx context.Context) (string, error)
+}
@@ -0,0 +1,82 @@
+package things_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/public-things-api/things"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	throttledUUID = "00000000-0000-002a-0000-000000000001"
+	goneUUID      = "00000000-0000-002a-0000-000000000002"
+	stableUUID    = "00000000-0000-002a-0000-000000000003"
+)
+
+// failingSource is a ConceptSource defined outside of the package, reporting failures the way the contract asks and
+// answering conditional reads of its only concept with ErrNotModified.
+type failingSource struct{}
+
+func (failingSource) Read(ctx context.Context, uuid string, relationships []string) (things.Concept, bool, error) {
+	switch uuid {
+	case throttledUUID:
+		return things.Concept{}, false, things.NewUpstreamError(http.StatusTooManyRequests, "30", nil)
+	case goneUUID:
+		return things.Concept{}, false, things.NewUpstreamError(http.StatusBadGateway, "", errors.New("store is gone"))
+	case stableUUID:
+		if !things.IfModifiedSince(ctx).IsZero() {
+			return things.Concept{}, false, things.ErrNotModified
+		}
+		return things.Concept{ID: "http://api.ft.com/things/" + stableUUID, PrefLabel: "Stable"}, true, nil
+	}
+	return things.Concept{}, false, nil
+}
+
+func (failingSource) Check(ctx context.Context) (string, error) {
+	return "failing on purpose", nil
+}
+
+func TestExternalSourceReportsUpstreamErrors(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := mux.NewRouter()
+	handler := things.NewSourceHandler(failingSource{})
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/"+throttledUUID, nil)
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+	assert.Equal(t, `{"message":"Error getting thing with uuid `+throttledUUID+`, err=Too Many Requests"}`, rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/things/"+goneUUID, nil)
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadGateway, rr.Code)
+	assert.Equal(t, `{"message":"Error getting thing with uuid `+goneUUID+`, err=store is gone"}`, rr.Body.String())
+}
+
+func TestUpstreamErrorWithoutCause(t *testing.T) {
+	assert.Equal(t, "Service Unavailable", (&things.UpstreamError{Status: http.StatusServiceUnavailable}).Error())
+}
+
+func TestExternalSourceNotModified(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	router := mux.NewRouter()
+	handler := things.NewSourceHandler(failingSource{})
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/"+stableUUID, nil)
+	req.Header.Set("If-Modified-Since", "Tue, 10 Oct 2017 10:00:00 GMT")
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code, "the thing is returned as its modification date is unknown")
+	assert.Contains(t, rr.Body.String(), `"prefLabel":"Stable"`)
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+}
@@ -0,0 +1,77 @@
+package things
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/transactionid-utils-go"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sourceHandler(source ConceptSource, options ...Option) *mux.Router {
+	router := mux.NewRouter()
+	handler := NewSourceHandler(source, options...)
+	handler.RegisterHandlers(router)
+	return router
+}
+
+func TestSourceHandlerReadsFromSource(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source := new(mockedSource)
+	withTransactionID := mock.MatchedBy(func(ctx context.Context) bool {
+		transID, _ := transactionidutils.GetTransactionIDFromContext(ctx)
+		return transID == "tid_source"
+	})
+	source.On("Read", withTransactionID, canonicalUUID, []string{"related"}).Return(Concept{
+		ID:         "http://api.ft.com/things/" + canonicalUUID,
+		APIURL:     "http://api.ft.com/things/" + canonicalUUID,
+		PrefLabel:  "From the source",
+		Types:      []string{"http://www.ft.com/ontology/Topic"},
+		DirectType: "http://www.ft.com/ontology/Topic",
+	}, true, nil)
+
+	rr := serveThing(sourceHandler(source), "/things/"+canonicalUUID+"?showRelationship=related",
+		http.Header{"X-Request-Id": []string{"tid_source"}})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"id":"http://api.ft.com/things/`+canonicalUUID+`","apiUrl":"http://api.ft.com/things/`+canonicalUUID+`",`+
+		`"prefLabel":"From the source","types":["http://www.ft.com/ontology/Topic"],"directType":"http://www.ft.com/ontology/Topic"}`+"\n",
+		rr.Body.String())
+	source.AssertExpectations(t)
+}
+
+func TestSourceHandlerReportsSourceFailures(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source := new(mockedSource)
+	source.On("Read", mock.Anything, canonicalUUID, []string(nil)).Return(Concept{}, false, nil)
+	source.On("Read", mock.Anything, alternateUUID, []string(nil)).
+		Return(Concept{}, false, &UpstreamError{Status: http.StatusServiceUnavailable, err: errors.New("store is down")})
+	router := sourceHandler(source)
+
+	rr := serveThing(router, "/things/"+canonicalUUID, nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = serveThing(router, "/things/"+alternateUUID, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, `{"message":"Error getting thing with uuid `+alternateUUID+`, err=store is down"}`, rr.Body.String())
+}
+
+func TestSourceHandlerChecksSource(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	source := new(mockedSource)
+	source.On("Check", mock.Anything).Return("", errors.New("store is down")).Once()
+	source.On("Check", mock.Anything).Return("Store is healthy", nil)
+	handler := NewSourceHandler(source, WithCircuitBreaker(1, 0), WithRetries(testRetryPolicy))
+
+	_, err := handler.HealthCheck().Checker()
+	assert.EqualError(t, err, "store is down")
+
+	output, err := handler.HealthCheck().Checker()
+	assert.NoError(t, err)
+	assert.Equal(t, "Store is healthy", output)
+}
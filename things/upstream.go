@@ -0,0 +1,90 @@
+package things
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+)
+
+// UpstreamError is returned when public-concepts-api could not be reached or answered with a response that
+// cannot be served as a thing. Status is the HTTP status the error should be reported to the caller with.
+type UpstreamError struct {
+	Status     int
+	RetryAfter string
+	err        error
+}
+
+// NewUpstreamError returns the error a ConceptSource reports a failure with, along with the HTTP status and the
+// Retry-After header, if any, the caller should get.
+func NewUpstreamError(status int, retryAfter string, err error) *UpstreamError {
+	return &UpstreamError{Status: status, RetryAfter: retryAfter, err: err}
+}
+
+func (e *UpstreamError) Error() string {
+	if e.err == nil {
+		return http.StatusText(e.Status)
+	}
+	return e.err.Error()
+}
+
+var (
+	errInvalidContentType = errors.New("public-concepts-api returned a non JSON response")
+	errMissingConceptID   = errors.New("public-concepts-api returned a concept without a valid id")
+)
+
+// classifyTransportError maps a failure to get any response from public-concepts-api to an UpstreamError.
+func classifyTransportError(err error) error {
+	if err == ErrCircuitOpen {
+		return err
+	}
+	if netErr, ok := err.(net.Error); (ok && netErr.Timeout()) || err == context.DeadlineExceeded {
+		return &UpstreamError{Status: http.StatusGatewayTimeout, err: err}
+	}
+	return &UpstreamError{Status: http.StatusServiceUnavailable, err: err}
+}
+
+// classifyResponse checks the status and content type of a response from public-concepts-api other than a 404.
+func classifyResponse(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		return &UpstreamError{
+			Status:     resp.StatusCode,
+			RetryAfter: resp.Header.Get("Retry-After"),
+			err:        fmt.Errorf("public-concepts-api returned HTTP status %d", resp.StatusCode),
+		}
+	default:
+		return &UpstreamError{
+			Status: http.StatusBadGateway,
+			err:    fmt.Errorf("public-concepts-api returned unexpected HTTP status %d", resp.StatusCode),
+		}
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && mediaType != "text/json") {
+			return &UpstreamError{Status: http.StatusBadGateway, err: errInvalidContentType}
+		}
+	}
+	return nil
+}
+
+// decodeConceptApiResponse unmarshals the body of a successful response and checks it describes a concept.
+func decodeConceptApiResponse(body []byte) (ConceptApiResponse, error) {
+	conceptsApiResponse := ConceptApiResponse{}
+	if err := json.Unmarshal(body, &conceptsApiResponse); err != nil {
+		return conceptsApiResponse, &UpstreamError{
+			Status: http.StatusBadGateway,
+			err:    fmt.Errorf("public-concepts-api returned an invalid response: %v", err),
+		}
+	}
+	if _, ok := uuidFromID(conceptsApiResponse.ID); !ok {
+		return conceptsApiResponse, &UpstreamError{Status: http.StatusBadGateway, err: errMissingConceptID}
+	}
+	return conceptsApiResponse, nil
+}
@@ -0,0 +1,111 @@
+package things
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpstreamResponseClassification(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	html := http.Header{"Content-Type": []string{"text/html; charset=UTF-8"}}
+	jsonHeader := http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}}
+
+	tests := []struct {
+		name               string
+		response           mockResponse
+		expectedCode       int
+		expectedRetryAfter string
+		expectedBody       string
+	}{
+		{
+			"internal server error with html body",
+			mockResponse{statusCode: 500, header: html, body: "<html>oops</html>"},
+			http.StatusBadGateway,
+			"",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=public-concepts-api returned unexpected HTTP status 500"}`,
+		},
+		{
+			"too many requests is passed through",
+			mockResponse{statusCode: 429, header: http.Header{"Retry-After": []string{"120"}}},
+			http.StatusTooManyRequests,
+			"120",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=public-concepts-api returned HTTP status 429"}`,
+		},
+		{
+			"service unavailable",
+			mockResponse{statusCode: 503, header: http.Header{}},
+			http.StatusServiceUnavailable,
+			"",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=public-concepts-api returned HTTP status 503"}`,
+		},
+		{
+			"gateway timeout",
+			mockResponse{statusCode: 504, header: http.Header{}},
+			http.StatusGatewayTimeout,
+			"",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=public-concepts-api returned HTTP status 504"}`,
+		},
+		{
+			"ok with html body",
+			mockResponse{statusCode: 200, header: html, body: "<html></html>"},
+			http.StatusBadGateway,
+			"",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=public-concepts-api returned a non JSON response"}`,
+		},
+		{
+			"ok with empty concept",
+			mockResponse{statusCode: 200, header: jsonHeader, body: "{}"},
+			http.StatusBadGateway,
+			"",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=public-concepts-api returned a concept without a valid id"}`,
+		},
+		{
+			"request timeout",
+			mockResponse{err: context.DeadlineExceeded},
+			http.StatusGatewayTimeout,
+			"",
+			`{"message":"Error getting thing with uuid 6773e864-78ab-4051-abc2-f4e9ab423ebb, err=context deadline exceeded"}`,
+		},
+	}
+
+	for _, test := range tests {
+		for _, url := range []string{"/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", "/things?uuid=6773e864-78ab-4051-abc2-f4e9ab423ebb"} {
+			router := mux.NewRouter()
+			handler := NewHandler(&sequenceHTTPClient{responses: []mockResponse{test.response}}, "localhost:8080")
+			handler.RegisterHandlers(router)
+
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", url, nil)
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedCode, rr.Code, test.name+" failed: status codes do not match!")
+			assert.Equal(t, test.expectedRetryAfter, rr.Header().Get("Retry-After"), test.name+" failed: Retry-After does not match!")
+			assert.Equal(t, test.expectedBody, rr.Body.String(), test.name+" failed: status body does not match!")
+		}
+	}
+}
+
+func TestUpstreamResponseWithJSONContentTypeIsAccepted(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &sequenceHTTPClient{responses: []mockResponse{{
+		statusCode: 200,
+		header:     http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}},
+		body:       getCompleteThingAsConcept,
+	}}}
+	router := mux.NewRouter()
+	handler := NewHandler(client, "localhost:8080")
+	handler.RegisterHandlers(router)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/things/6773e864-78ab-4051-abc2-f4e9ab423ebb", nil)
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, transformBody(transformedCompleteThing), rr.Body.String())
+}
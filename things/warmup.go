@@ -0,0 +1,133 @@
+package things
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/Financial-Times/service-status-go/gtg"
+	"github.com/Financial-Times/transactionid-utils-go"
+)
+
+var warmUpUUIDRegexp = regexp.MustCompile(validUUID)
+
+// warmUp tracks the preloading of the cache with the things most likely to be requested.
+type warmUp struct {
+	uuids       []string
+	concurrency int
+	loaded      int64
+	done        chan struct{}
+}
+
+// WithWarmUp preloads the cache with the things of the given uuids when WarmUp is called, fetching at most
+// concurrency of them at once. The handler is not good to go until the warm-up is over.
+func WithWarmUp(uuids []string, concurrency int) Option {
+	return func(h *ThingsHandler) {
+		if len(uuids) == 0 {
+			return
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		h.warmUp = &warmUp{uuids: uuids, concurrency: concurrency, done: make(chan struct{})}
+	}
+}
+
+// ReadWarmUpUUIDs reads the uuids to preload the cache with, either one per line or as JSON lines with a uuid or id
+// field. Blank lines and lines starting with # are ignored, as well as lines without a valid uuid.
+func ReadWarmUpUUIDs(r io.Reader) ([]string, error) {
+	var uuids []string
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			var entry struct {
+				UUID string `json:"uuid"`
+				ID   string `json:"id"`
+			}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				logger.WithError(err).Warnf("Ignoring line %d of the warm-up list, it is not valid JSON", lineNumber)
+				continue
+			}
+			line = entry.UUID
+			if line == "" {
+				line = entry.ID
+			}
+		}
+		uuid := strings.ToLower(warmUpUUIDRegexp.FindString(line))
+		if uuid == "" {
+			logger.Warnf("Ignoring line %d of the warm-up list, it does not hold a uuid", lineNumber)
+			continue
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, scanner.Err()
+}
+
+// WarmUp preloads the cache with the things configured by WithWarmUp, returning once they are all loaded or failed.
+func (h *ThingsHandler) WarmUp() {
+	if h.warmUp == nil {
+		return
+	}
+	defer close(h.warmUp.done)
+	if h.cache == nil {
+		logger.Warn("Skipping the warm-up as the cache is disabled")
+		return
+	}
+
+	transID := transactionidutils.NewTransactionID()
+	logger.WithTransactionID(transID).Infof("Warming up the cache with %d things", len(h.warmUp.uuids))
+
+	uuidCh := make(chan string, len(h.warmUp.uuids))
+	for _, uuid := range h.warmUp.uuids {
+		uuidCh <- uuid
+	}
+	close(uuidCh)
+
+	var failed int64
+	var wg sync.WaitGroup
+	wg.Add(h.warmUp.concurrency)
+	for i := 0; i < h.warmUp.concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for uuid := range uuidCh {
+				ctx, cancel := h.boundedContext(context.Background())
+				if _, _, err := h.getThing(ctx, uuid, nil, transID); err != nil {
+					atomic.AddInt64(&failed, 1)
+				}
+				cancel()
+				atomic.AddInt64(&h.warmUp.loaded, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	logger.WithTransactionID(transID).Infof("Warmed up the cache, %d of %d things failed to load", failed, len(h.warmUp.uuids))
+}
+
+// warmUpStatus reports the handler as not good to go until the warm-up is over.
+func (h *ThingsHandler) warmUpStatus() gtg.Status {
+	if h.warmUp == nil {
+		return gtg.Status{GoodToGo: true}
+	}
+	select {
+	case <-h.warmUp.done:
+		return gtg.Status{GoodToGo: true}
+	default:
+		return gtg.Status{
+			GoodToGo: false,
+			Message: fmt.Sprintf("Warming up the cache, %d of %d things loaded",
+				atomic.LoadInt64(&h.warmUp.loaded), len(h.warmUp.uuids)),
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package things
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/go-logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWarmUpUUIDs(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	list := `# hot things
+6773e864-78ab-4051-abc2-f4e9ab423ebb
+
+  6773E864-78AB-4051-ABC2-F4E9AB423EBC  
+{"uuid":"6773e864-78ab-4051-abc2-f4e9ab423ebd","prefLabel":"Brussels blog"}
+{"id":"http://api.ft.com/things/6773e864-78ab-4051-abc2-f4e9ab423ebe"}
+{"prefLabel":"no uuid"}
+not a uuid
+{broken`
+
+	uuids, err := ReadWarmUpUUIDs(strings.NewReader(list))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"6773e864-78ab-4051-abc2-f4e9ab423ebb",
+		"6773e864-78ab-4051-abc2-f4e9ab423ebc",
+		"6773e864-78ab-4051-abc2-f4e9ab423ebd",
+		"6773e864-78ab-4051-abc2-f4e9ab423ebe",
+	}, uuids)
+}
+
+func TestWarmUpPreloadsCacheBeforeGoodToGo(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{
+		"/__gtg": {statusCode: 200},
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb": {statusCode: 200, body: getCompleteThingAsConcept},
+		"/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebd": {statusCode: 503},
+	}}
+	uuids := []string{"6773e864-78ab-4051-abc2-f4e9ab423ebb", "6773e864-78ab-4051-abc2-f4e9ab423ebc", "6773e864-78ab-4051-abc2-f4e9ab423ebd"}
+	handler := NewHandler(client, "http://localhost:8080", WithConceptCache(10, time.Minute), WithWarmUp(uuids, 2))
+
+	status := handler.GTG()
+	assert.False(t, status.GoodToGo)
+	assert.Equal(t, "Warming up the cache, 0 of 3 things loaded", status.Message)
+
+	handler.WarmUp()
+
+	assert.True(t, handler.GTG().GoodToGo, "failures to load some things should not prevent the handler from being good to go")
+	_, found := handler.cache.get(cacheKey("6773e864-78ab-4051-abc2-f4e9ab423ebb", nil))
+	assert.True(t, found)
+	for _, uuid := range uuids {
+		assert.Equal(t, 1, client.calls["/concepts/"+uuid])
+	}
+}
+
+func TestWarmUpWithoutCacheIsSkipped(t *testing.T) {
+	logger.InitLogger("test service", "debug")
+	client := &pathHTTPClient{responses: map[string]mockResponse{"/__gtg": {statusCode: 200}}}
+	handler := NewHandler(client, "http://localhost:8080", WithWarmUp([]string{"6773e864-78ab-4051-abc2-f4e9ab423ebb"}, 2))
+
+	handler.WarmUp()
+
+	assert.True(t, handler.GTG().GoodToGo)
+	assert.Equal(t, 0, client.calls["/concepts/6773e864-78ab-4051-abc2-f4e9ab423ebb"])
+}